@@ -23,6 +23,20 @@ If release name contains chart name it will be used as a full name.
 {{- end }}
 {{- end }}
 
+{{/*
+Name of the Deployment of a shard, from a list of the root context and the
+shard index: the full name when not sharded.
+*/}}
+{{- define "namespace-operator.shardName" -}}
+{{- $root := index . 0 }}
+{{- $shard := index . 1 }}
+{{- if gt (int $root.Values.sharding.shards) 1 }}
+{{- printf "%s-shard-%d" (include "namespace-operator.fullname" $root | trunc 55 | trimSuffix "-") $shard }}
+{{- else }}
+{{- include "namespace-operator.fullname" $root }}
+{{- end }}
+{{- end }}
+
 {{/*
 Create chart name and version as used by the chart label.
 */}}
@@ -60,3 +74,17 @@ Create the name of the service account to use
 {{- default "default" .Values.serviceAccount.name }}
 {{- end }}
 {{- end }}
+
+{{/*
+Name of the webhook serving certificate secret
+*/}}
+{{- define "namespace-operator.webhookCertSecret" -}}
+{{- printf "%s-webhook-cert" (include "namespace-operator.fullname" .) | trunc 63 | trimSuffix "-" }}
+{{- end }}
+
+{{/*
+Name of the webhook service
+*/}}
+{{- define "namespace-operator.webhookService" -}}
+{{- printf "%s-webhook" (include "namespace-operator.fullname" .) | trunc 63 | trimSuffix "-" }}
+{{- end }}
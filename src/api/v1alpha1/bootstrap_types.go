@@ -0,0 +1,57 @@
+package v1alpha1
+
+// Kinds that can be copied into tenant namespaces
+const (
+	BootstrapKindSecret    = "Secret"
+	BootstrapKindConfigMap = "ConfigMap"
+)
+
+// BootstrapSpec lists the objects created in every namespace using the profile.
+type BootstrapSpec struct {
+	// Secrets (in the tenant namespace) added to the imagePullSecrets of the
+	// default ServiceAccount
+	// +optional
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+
+	// Secrets and ConfigMaps copied from another namespace and kept in sync
+	// +optional
+	Copy []BootstrapCopy `json:"copy,omitempty"`
+
+	// ConfigMaps rendered from Go templates
+	// +optional
+	ConfigMaps []ConfigMapTemplate `json:"configMaps,omitempty"`
+}
+
+// BootstrapCopy references a Secret or ConfigMap to copy into the namespace
+type BootstrapCopy struct {
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	Kind string `json:"kind"`
+
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:MinLength=1
+	SourceNamespace string `json:"sourceNamespace"`
+
+	// Name of the copy, defaults to name
+	// +optional
+	TargetName string `json:"targetName,omitempty"`
+}
+
+// TargetObjectName returns the name of the copy in the tenant namespace.
+func (c BootstrapCopy) TargetObjectName() string {
+	if c.TargetName != "" {
+		return c.TargetName
+	}
+	return c.Name
+}
+
+// ConfigMapTemplate renders a ConfigMap in the namespace. Data values are Go
+// templates with .Tenant, .Namespace and .Profile available.
+type ConfigMapTemplate struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+}
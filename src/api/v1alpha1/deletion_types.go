@@ -0,0 +1,14 @@
+package v1alpha1
+
+// Deletion policies of the namespace of a deleted Tenant
+const (
+	// DeletionPolicyDelete deletes the namespace with the Tenant
+	DeletionPolicyDelete = "Delete"
+
+	// DeletionPolicyRetain releases the namespace: it is no longer owned nor
+	// managed by the operator, but kept with its content
+	DeletionPolicyRetain = "Retain"
+)
+
+// DeletionPolicies lists the valid deletion policies
+var DeletionPolicies = []string{DeletionPolicyDelete, DeletionPolicyRetain}
@@ -0,0 +1,6 @@
+package v1alpha1
+
+// ExtendUntilAnnotation postpones the expiration of a tenant to the given
+// RFC 3339 time. It is ignored when earlier than spec.expiresAt or the end of
+// spec.ttl.
+const ExtendUntilAnnotation = "platform.example.com/extend-until"
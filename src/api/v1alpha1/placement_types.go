@@ -0,0 +1,25 @@
+package v1alpha1
+
+import corev1 "k8s.io/api/core/v1"
+
+// Namespace annotations read by the PodNodeSelector and
+// PodTolerationRestriction admission plugins, and by the placement webhook
+const (
+	SchedulerAnnotationPrefix    = "scheduler.alpha.kubernetes.io/"
+	NodeSelectorAnnotation       = SchedulerAnnotationPrefix + "node-selector"
+	DefaultTolerationsAnnotation = SchedulerAnnotationPrefix + "defaultTolerations"
+)
+
+// PlacementSpec pins the pods of a tenant to a node pool. It is written to
+// the namespace scheduler annotations (PodNodeSelector and
+// PodTolerationRestriction admission plugins) and injected by the pod
+// placement webhook when enabled.
+type PlacementSpec struct {
+	// Node labels every pod of the tenant must run on
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations added to every pod of the tenant (e.g. for the pool taint)
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
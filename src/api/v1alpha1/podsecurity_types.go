@@ -0,0 +1,71 @@
+package v1alpha1
+
+// Pod Security Standards levels, from least to most restrictive
+const (
+	PodSecurityLevelPrivileged = "privileged"
+	PodSecurityLevelBaseline   = "baseline"
+	PodSecurityLevelRestricted = "restricted"
+)
+
+// PodSecuritySpec configures the Pod Security Admission labels of the namespace.
+// Unset levels default to restricted; unset versions default to latest.
+type PodSecuritySpec struct {
+	// +kubebuilder:validation:Enum=privileged;baseline;restricted
+	// +optional
+	Enforce string `json:"enforce,omitempty"`
+
+	// +optional
+	EnforceVersion string `json:"enforceVersion,omitempty"`
+
+	// +kubebuilder:validation:Enum=privileged;baseline;restricted
+	// +optional
+	Audit string `json:"audit,omitempty"`
+
+	// +optional
+	AuditVersion string `json:"auditVersion,omitempty"`
+
+	// +kubebuilder:validation:Enum=privileged;baseline;restricted
+	// +optional
+	Warn string `json:"warn,omitempty"`
+
+	// +optional
+	WarnVersion string `json:"warnVersion,omitempty"`
+}
+
+// PodSecurityProfileSpec holds the profile defaults and the lowest enforce
+// level tenants using the profile may request.
+type PodSecurityProfileSpec struct {
+	PodSecuritySpec `json:",inline"`
+
+	// Lowest enforce level a Tenant may set; defaults to the profile enforce level
+	// +kubebuilder:validation:Enum=privileged;baseline;restricted
+	// +optional
+	MinimumEnforce string `json:"minimumEnforce,omitempty"`
+}
+
+// PodSecurityLevelRank orders Pod Security Standards levels; unknown levels rank lowest.
+func PodSecurityLevelRank(level string) int {
+	switch level {
+	case PodSecurityLevelRestricted:
+		return 2
+	case PodSecurityLevelBaseline:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// MinimumEnforceLevel returns the lowest enforce level a Tenant using the
+// profile may set. A profile without pod security settings only allows restricted.
+func (p *PodSecurityProfileSpec) MinimumEnforceLevel() string {
+	switch {
+	case p == nil:
+		return PodSecurityLevelRestricted
+	case p.MinimumEnforce != "":
+		return p.MinimumEnforce
+	case p.Enforce != "":
+		return p.Enforce
+	default:
+		return PodSecurityLevelRestricted
+	}
+}
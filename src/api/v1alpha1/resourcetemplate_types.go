@@ -0,0 +1,31 @@
+package v1alpha1
+
+import "k8s.io/apimachinery/pkg/runtime"
+
+// ResourceTemplate is a namespaced object applied into every tenant namespace
+// using the profile. Exactly one of object or template must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.object) != has(self.template)",message="exactly one of object or template must be set"
+type ResourceTemplate struct {
+	// Name identifying the template
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Structured object; string values are Go templates with .Tenant,
+	// .Namespace and .Profile available
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:EmbeddedResource
+	// +optional
+	Object *runtime.RawExtension `json:"object,omitempty"`
+
+	// YAML manifest rendered as a Go template with .Tenant, .Namespace and
+	// .Profile available
+	// +optional
+	Template string `json:"template,omitempty"`
+}
+
+// ResourceReference identifies an object applied from a resource template
+type ResourceReference struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
@@ -0,0 +1,23 @@
+package v1alpha1
+
+// WakeUntilAnnotation keeps a hibernating tenant awake until the given
+// RFC 3339 time, overriding its schedule.
+const WakeUntilAnnotation = "platform.example.com/wake-until"
+
+// HibernationSchedule puts a tenant to sleep (as with spec.suspended) between
+// a sleep and the following wake time. Both are standard 5-field cron
+// expressions, e.g. sleep "0 19 * * 1-5" and wake "0 7 * * 1-5" for nights
+// and weekends.
+type HibernationSchedule struct {
+	// Cron expression at which the tenant goes to sleep
+	// +kubebuilder:validation:MinLength=1
+	Sleep string `json:"sleep"`
+
+	// Cron expression at which the tenant wakes up
+	// +kubebuilder:validation:MinLength=1
+	Wake string `json:"wake"`
+
+	// IANA time zone of the cron expressions, defaults to UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
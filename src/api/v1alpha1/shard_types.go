@@ -0,0 +1,6 @@
+package v1alpha1
+
+// ShardLabel pins a Tenant to one shard when the operator runs sharded, for
+// example platform.example.com/shard=2. Tenants without it are spread across
+// shards by a hash of their name.
+const ShardLabel = "platform.example.com/shard"
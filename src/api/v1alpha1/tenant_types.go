@@ -4,6 +4,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// +kubebuilder:validation:XValidation:rule="!(has(self.expiresAt) && has(self.ttl))",message="expiresAt and ttl are mutually exclusive"
 type TenantSpec struct {
 	// Namespace to create/manage
 	Namespace string `json:"namespace"`
@@ -22,6 +23,82 @@ type TenantSpec struct {
 	// Network policy rules (ingress/egress)
 	// +optional
 	Network *NetworkSpec `json:"network,omitempty"`
+
+	// Extra labels and annotations for the namespace, merged over the profile defaults
+	// +optional
+	NamespaceMetadata *NamespaceMetadata `json:"namespaceMetadata,omitempty"`
+
+	// Pod Security Standards levels, cannot go below the profile minimum
+	// +optional
+	PodSecurity *PodSecuritySpec `json:"podSecurity,omitempty"`
+
+	// Access granted in the namespace, added to the profile default bindings
+	// +optional
+	Access []AccessBinding `json:"access,omitempty"`
+
+	// Registries or image prefixes allowed in addition to the profile allowedRegistries
+	// +optional
+	RegistryExceptions []string `json:"registryExceptions,omitempty"`
+
+	// Scale workloads to zero, suspend CronJobs and block new pods
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+
+	// Recurring hibernation windows (nights, weekends)
+	// +optional
+	Schedule *HibernationSchedule `json:"schedule,omitempty"`
+
+	// Time at which the tenant and its namespace are deleted
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Lifetime of the tenant from its creation, e.g. 72h
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// What happens to the namespace when the tenant is deleted, defaults to
+	// the operator defaultDeletionPolicy
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// AccessBinding grants a role in the tenant namespace to a set of subjects
+type AccessBinding struct {
+	// Role to bind: admin, edit, view or another ClusterRole allowed by the operator
+	// +kubebuilder:validation:MinLength=1
+	Role string `json:"role"`
+
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// +optional
+	ServiceAccounts []ServiceAccountReference `json:"serviceAccounts,omitempty"`
+}
+
+// ServiceAccountReference identifies a ServiceAccount subject
+type ServiceAccountReference struct {
+	Name string `json:"name"`
+
+	// Defaults to the tenant namespace
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// NamespaceMetadata holds labels and annotations to set on the managed namespace.
+// Keys under the platform.example.com/ and pod-security.kubernetes.io/ prefixes
+// and managed-by are reserved, as are scheduler.alpha.kubernetes.io/ annotations.
+type NamespaceMetadata struct {
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k != 'managed-by' && !k.startsWith('platform.example.com/') && !k.startsWith('pod-security.kubernetes.io/'))",message="managed-by, platform.example.com/ and pod-security.kubernetes.io/ keys are reserved"
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self.all(k, !k.startsWith('platform.example.com/') && !k.startsWith('scheduler.alpha.kubernetes.io/'))",message="platform.example.com/ and scheduler.alpha.kubernetes.io/ keys are reserved"
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // NetworkSpec définit les règles réseau personnalisées pour un tenant
@@ -91,12 +168,46 @@ type LimitSpec struct {
 
 // +kubebuilder:object:generate=true
 type TenantStatus struct {
+	// ObservedGeneration is the Tenant generation last applied to the cluster
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ObservedProfileGeneration is the TenantProfile generation last applied
+	// +optional
+	ObservedProfileGeneration int64 `json:"observedProfileGeneration,omitempty"`
+
+	// RoleBindings managed in the tenant namespace
+	// +optional
+	RoleBindings []string `json:"roleBindings,omitempty"`
+
+	// Objects applied from the profile resource templates
+	// +optional
+	Resources []ResourceReference `json:"resources,omitempty"`
+
+	// StorageClasses the tenant can use
+	// +optional
+	AllowedStorageClasses []string `json:"allowedStorageClasses,omitempty"`
+
+	// PriorityClasses the tenant pods can use
+	// +optional
+	AllowedPriorityClasses []string `json:"allowedPriorityClasses,omitempty"`
+
+	// Time the tenant was suspended, unset while active
+	// +optional
+	SuspendedSince *metav1.Time `json:"suspendedSince,omitempty"`
+
+	// Time at which the tenant expires, extensions included
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,shortName=tenant
+// +kubebuilder:selectablefield:JSONPath=`.spec.namespace`
+// +kubebuilder:validation:XValidation:rule="size(self.metadata.name) <= 63",message="name must be no more than 63 characters, it is used as a label value"
 type Tenant struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -2,9 +2,16 @@ package v1alpha1
 
 import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+// Registry policy modes
+const (
+	RegistryPolicyEnforce = "Enforce"
+	RegistryPolicyAudit   = "Audit"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster,shortName=tp
 // +kubebuilder:subresource:status
+// +kubebuilder:validation:XValidation:rule="size(self.metadata.name) <= 63",message="name must be no more than 63 characters, it is used as a label value"
 type TenantProfile struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -15,6 +22,78 @@ type TenantProfile struct {
 type TenantProfileSpec struct {
 	Quota  QuotaSpec `json:"quota"`
 	Limits LimitSpec `json:"limits"`
+
+	// Default namespace labels and annotations (cost-center, team, mesh injection, ...)
+	// +optional
+	NamespaceMetadata *NamespaceMetadata `json:"namespaceMetadata,omitempty"`
+
+	// Default Pod Security Standards levels and the minimum tenants may request
+	// +optional
+	PodSecurity *PodSecurityProfileSpec `json:"podSecurity,omitempty"`
+
+	// Default access bindings for every tenant using the profile (e.g. platform on-call)
+	// +optional
+	Access []AccessBinding `json:"access,omitempty"`
+
+	// Objects created in every namespace (pull secrets, CA bundles, ...)
+	// +optional
+	Bootstrap *BootstrapSpec `json:"bootstrap,omitempty"`
+
+	// Node pool the tenant pods are pinned to
+	// +optional
+	Placement *PlacementSpec `json:"placement,omitempty"`
+
+	// StorageClasses tenants may use; unset allows every class
+	// +optional
+	AllowedStorageClasses []string `json:"allowedStorageClasses,omitempty"`
+
+	// PriorityClasses tenant pods may use; unset allows every class
+	// +optional
+	AllowedPriorityClasses []string `json:"allowedPriorityClasses,omitempty"`
+
+	// Registries (or image prefixes such as ghcr.io/my-org) tenant images may
+	// come from; unset allows every registry
+	// +optional
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+
+	// Enforce rejects images from other registries, Audit only warns and counts them
+	// +kubebuilder:validation:Enum=Enforce;Audit
+	// +kubebuilder:default=Enforce
+	// +optional
+	RegistryPolicy string `json:"registryPolicy,omitempty"`
+
+	// Additional objects (PodDisruptionBudgets, ServiceMonitors, Roles, ...)
+	// applied into every namespace
+	// +optional
+	Resources []ResourceTemplate `json:"resources,omitempty"`
+
+	// Self-service settings for TenantRequests using this profile
+	// +optional
+	SelfService *SelfServiceSpec `json:"selfService,omitempty"`
+}
+
+// SelfServiceSpec controls how TenantRequests may use a profile
+type SelfServiceSpec struct {
+	// Allow TenantRequests to use this profile
+	Enabled bool `json:"enabled"`
+
+	// Create the Tenant without waiting for an approval annotation
+	// +optional
+	AutoApprove bool `json:"autoApprove,omitempty"`
+
+	// Groups allowed to request tenants with this profile; empty allows any group
+	// +optional
+	AllowedGroups []string `json:"allowedGroups,omitempty"`
+
+	// ClusterRoles the access of a TenantRequest may grant, on top of admin
+	// for the owning group; empty allows no additional access
+	// +optional
+	AllowedRoles []string `json:"allowedRoles,omitempty"`
+
+	// Maximum number of tenants a group may own; 0 means unlimited
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxTenantsPerGroup int32 `json:"maxTenantsPerGroup,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,93 @@
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// TenantRequest phases
+const (
+	TenantRequestPending     = "Pending"
+	TenantRequestDenied      = "Denied"
+	TenantRequestProvisioned = "Provisioned"
+)
+
+// Annotations used by the self-service flow. Requester and approver are
+// recorded by the admission webhook from the authenticated user.
+const (
+	TenantRequestApprovedAnnotation   = "platform.example.com/approved"
+	TenantRequestApprovedByAnnotation = "platform.example.com/approved-by"
+	TenantRequestRequesterAnnotation  = "platform.example.com/requester"
+
+	// Set on Tenants created from a request
+	TenantRequestAnnotation = "platform.example.com/tenant-request"
+	OwnerGroupAnnotation    = "platform.example.com/owner-group"
+)
+
+type TenantRequestSpec struct {
+	// Namespace to create; also used as the Tenant name
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Namespace string `json:"namespace"`
+
+	// Profile to use, must allow self-service
+	// +kubebuilder:validation:MinLength=1
+	Profile string `json:"profile"`
+
+	// Group owning the tenant, granted admin in the namespace
+	// +kubebuilder:validation:MinLength=1
+	Group string `json:"group"`
+
+	// Additional access bindings, limited to the roles allowed by the
+	// profile selfService and to ServiceAccounts of the tenant namespace
+	// +optional
+	Access []AccessBinding `json:"access,omitempty"`
+
+	// +optional
+	NamespaceMetadata *NamespaceMetadata `json:"namespaceMetadata,omitempty"`
+}
+
+type TenantRequestStatus struct {
+	// Pending, Denied or Provisioned
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// Human readable reason for the current phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// User who created the request, recorded when the webhook is enabled
+	// +optional
+	Requester string `json:"requester,omitempty"`
+
+	// User who approved the request, or "auto-approved"
+	// +optional
+	Approver string `json:"approver,omitempty"`
+
+	// Name of the Tenant created for this request
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,shortName=treq
+// +kubebuilder:printcolumn:name="Profile",type=string,JSONPath=`.spec.profile`
+// +kubebuilder:printcolumn:name="Group",type=string,JSONPath=`.spec.group`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+type TenantRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TenantRequestSpec   `json:"spec,omitempty"`
+	Status TenantRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type TenantRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TenantRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TenantRequest{}, &TenantRequestList{})
+}
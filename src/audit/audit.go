@@ -0,0 +1,276 @@
+// Package audit records the changes the operator makes to the cluster as
+// structured events, separately from the debug logs.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gomodules.xyz/jsonpatch/v2"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Action is the kind of change made to an object
+type Action string
+
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// -----------------------------------------------------------------------------
+// Event is one audited change. Before and After hold the object without its
+// server-managed metadata and status; Diff is the JSON Patch from Before to
+// After, set on updates. Secret values are redacted: the diff of a Secret
+// only tells which keys changed.
+// -----------------------------------------------------------------------------
+type Event struct {
+	Time       time.Time `json:"time"`
+	Controller string    `json:"controller,omitempty"`
+	Action     Action    `json:"action"`
+	Reason     string    `json:"reason"`
+
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+
+	// Tenant whose reconcile made the change, and its generation
+	Tenant           string `json:"tenant,omitempty"`
+	TenantGeneration int64  `json:"tenantGeneration,omitempty"`
+
+	Before json.RawMessage       `json:"before,omitempty"`
+	After  json.RawMessage       `json:"after,omitempty"`
+	Diff   []jsonpatch.Operation `json:"diff,omitempty"`
+}
+
+// Sink receives audit events. Implementations must be safe for concurrent use.
+type Sink interface {
+	Write(Event) error
+}
+
+// -----------------------------------------------------------------------------
+// JSONLines writes each event as one JSON document per line.
+// -----------------------------------------------------------------------------
+type JSONLines struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func NewJSONLines(w io.Writer) *JSONLines {
+	return &JSONLines{enc: json.NewEncoder(w)}
+}
+
+func (s *JSONLines) Write(e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(e)
+}
+
+// -----------------------------------------------------------------------------
+// Open returns the sink for target: "stdout", or the path of a file events
+// are appended to. The returned closer releases the file.
+// -----------------------------------------------------------------------------
+func Open(target string) (Sink, io.Closer, error) {
+	if target == "stdout" || target == "-" {
+		return NewJSONLines(os.Stdout), io.NopCloser(nil), nil
+	}
+
+	f, err := os.OpenFile(target, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewJSONLines(f), f, nil
+}
+
+// -----------------------------------------------------------------------------
+// Context plumbing: reconcilers store the sink and what triggered the
+// reconcile, and the code making the change only records it.
+// -----------------------------------------------------------------------------
+
+type contextKey struct{}
+
+type source struct {
+	sink             Sink
+	controller       string
+	tenant           string
+	tenantGeneration int64
+}
+
+// NewContext returns a context recording changes made by controller to sink.
+// A nil sink disables auditing.
+func NewContext(ctx context.Context, sink Sink, controller string) context.Context {
+	if sink == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, source{sink: sink, controller: controller})
+}
+
+// WithTenant attributes the changes recorded with ctx to a Tenant generation
+func WithTenant(ctx context.Context, name string, generation int64) context.Context {
+	src, ok := ctx.Value(contextKey{}).(source)
+	if !ok {
+		return ctx
+	}
+	src.tenant, src.tenantGeneration = name, generation
+	return context.WithValue(ctx, contextKey{}, src)
+}
+
+// -----------------------------------------------------------------------------
+// Record audits a change. before is nil on creation and after on deletion.
+// Write failures are logged, they never fail the reconcile.
+// -----------------------------------------------------------------------------
+func Record(
+	ctx context.Context,
+	action Action,
+	reason string,
+	before, after client.Object,
+) {
+	src, ok := ctx.Value(contextKey{}).(source)
+	if !ok {
+		return
+	}
+
+	obj := after
+	if obj == nil {
+		obj = before
+	}
+
+	e := Event{
+		Time:             time.Now().UTC(),
+		Controller:       src.controller,
+		Action:           action,
+		Reason:           reason,
+		Namespace:        obj.GetNamespace(),
+		Name:             obj.GetName(),
+		Tenant:           src.tenant,
+		TenantGeneration: src.tenantGeneration,
+	}
+	e.APIVersion, e.Kind = kindOf(before, after)
+
+	secret := isSecret(e.APIVersion, e.Kind)
+
+	var err error
+	if e.Before, err = sanitize(before); err == nil {
+		e.After, err = sanitize(after)
+	}
+	if err == nil && action == Update {
+		e.Diff, err = jsonpatch.CreatePatch(e.Before, e.After)
+	}
+	if err == nil && secret {
+		e.Diff = redactDiff(e.Diff)
+		if e.Before, err = redact(e.Before); err == nil {
+			e.After, err = redact(e.After)
+		}
+	}
+	if err == nil {
+		err = src.sink.Write(e)
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "unable to write audit event",
+			"kind", e.Kind, "namespace", e.Namespace, "name", e.Name)
+	}
+}
+
+// kindOf returns the apiVersion and kind of the objects. Typed objects read
+// from the API may have no type meta: the Go type name is then the kind.
+func kindOf(objs ...client.Object) (string, string) {
+	for _, obj := range objs {
+		if obj == nil {
+			continue
+		}
+		if gvk := obj.GetObjectKind().GroupVersionKind(); gvk.Kind != "" {
+			return gvk.GroupVersion().String(), gvk.Kind
+		}
+	}
+	for _, obj := range objs {
+		if obj != nil {
+			return "", reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+		}
+	}
+	return "", ""
+}
+
+// sanitize serializes obj without its status and server-managed metadata
+func sanitize(obj client.Object) (json.RawMessage, error) {
+	if obj == nil || reflect.ValueOf(obj).IsNil() {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	for _, k := range []string{"apiVersion", "kind", "status"} {
+		delete(m, k)
+	}
+	if meta, ok := m["metadata"].(map[string]any); ok {
+		for _, k := range []string{"managedFields", "resourceVersion", "uid", "generation", "creationTimestamp"} {
+			delete(meta, k)
+		}
+	}
+
+	return json.Marshal(m)
+}
+
+// -----------------------------------------------------------------------------
+// Secret redaction: the values under data and stringData are replaced, the
+// keys are kept so the audit log still tells what changed.
+// -----------------------------------------------------------------------------
+
+const redacted = "REDACTED"
+
+var secretFields = []string{"data", "stringData"}
+
+func isSecret(apiVersion, kind string) bool {
+	return kind == "Secret" && (apiVersion == "" || apiVersion == "v1")
+}
+
+// redact replaces the values of a serialized Secret
+func redact(raw json.RawMessage) (json.RawMessage, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	for _, field := range secretFields {
+		values, ok := m[field].(map[string]any)
+		if !ok {
+			continue
+		}
+		for k := range values {
+			values[k] = redacted
+		}
+	}
+
+	return json.Marshal(m)
+}
+
+// redactDiff drops the values of the operations on Secret data
+func redactDiff(diff []jsonpatch.Operation) []jsonpatch.Operation {
+	for i := range diff {
+		for _, field := range secretFields {
+			if diff[i].Path == "/"+field || strings.HasPrefix(diff[i].Path, "/"+field+"/") {
+				diff[i].Value = redacted
+			}
+		}
+	}
+	return diff
+}
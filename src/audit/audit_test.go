@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func quota(cpu string) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "tenant-quota",
+			Namespace:       "team-a",
+			ResourceVersion: "42",
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+		},
+	}
+}
+
+func TestRecord(t *testing.T) {
+	var buf bytes.Buffer
+
+	ctx := NewContext(context.Background(), NewJSONLines(&buf), "tenant")
+	ctx = WithTenant(ctx, "team-a", 3)
+
+	Record(ctx, Update, "Updated", quota("2"), quota("4"))
+	Record(ctx, Delete, "Pruned", quota("4"), nil)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSON lines, got %d: %s", len(lines), buf.String())
+	}
+
+	var update Event
+	if err := json.Unmarshal(lines[0], &update); err != nil {
+		t.Fatalf("invalid JSON line: %v", err)
+	}
+	if update.Controller != "tenant" || update.Tenant != "team-a" || update.TenantGeneration != 3 {
+		t.Fatalf("unexpected source %+v", update)
+	}
+	if update.Kind != "ResourceQuota" || update.Namespace != "team-a" || update.Name != "tenant-quota" {
+		t.Fatalf("unexpected object %s %s/%s", update.Kind, update.Namespace, update.Name)
+	}
+	if len(update.Diff) != 1 || update.Diff[0].Path != "/spec/hard/cpu" || update.Diff[0].Value != "4" {
+		t.Fatalf("unexpected diff %+v", update.Diff)
+	}
+	if bytes.Contains(update.Before, []byte("resourceVersion")) {
+		t.Fatalf("expected server-managed metadata to be dropped, got %s", update.Before)
+	}
+
+	var deletion Event
+	if err := json.Unmarshal(lines[1], &deletion); err != nil {
+		t.Fatalf("invalid JSON line: %v", err)
+	}
+	if deletion.Action != Delete || deletion.Reason != "Pruned" || deletion.After != nil || deletion.Diff != nil {
+		t.Fatalf("unexpected deletion event %+v", deletion)
+	}
+}
+
+func TestRecordWithoutSink(t *testing.T) {
+	ctx := NewContext(context.Background(), nil, "tenant")
+
+	// Nothing to write to: must not panic
+	Record(WithTenant(ctx, "team-a", 1), Create, "Created", nil, quota("2"))
+}
+
+func TestRecordRedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+
+	ctx := NewContext(context.Background(), NewJSONLines(&buf), "tenant")
+
+	secret := func(password string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "team-a"},
+			Data:       map[string][]byte{"password": []byte(password), "user": []byte("ci")},
+			StringData: map[string]string{"token": password},
+		}
+	}
+
+	Record(ctx, Update, "Updated", secret("hunter2"), secret("correct-horse"))
+
+	if bytes.Contains(buf.Bytes(), []byte("hunter2")) || bytes.Contains(buf.Bytes(), []byte("correct-horse")) ||
+		bytes.Contains(buf.Bytes(), []byte(base64.StdEncoding.EncodeToString([]byte("hunter2")))) {
+		t.Fatalf("expected Secret values to be redacted, got %s", buf.String())
+	}
+
+	var update Event
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &update); err != nil {
+		t.Fatalf("invalid JSON line: %v", err)
+	}
+	if update.Kind != "Secret" || !bytes.Contains(update.Before, []byte(`"user":"REDACTED"`)) {
+		t.Fatalf("expected the Secret keys to be kept, got %s", update.Before)
+	}
+
+	paths := map[string]bool{}
+	for _, op := range update.Diff {
+		paths[op.Path] = true
+		if op.Value != redacted {
+			t.Fatalf("expected redacted diff values, got %+v", op)
+		}
+	}
+	if len(paths) != 2 || !paths["/data/password"] || !paths["/stringData/token"] {
+		t.Fatalf("expected the changed keys in the diff, got %+v", update.Diff)
+	}
+}
@@ -0,0 +1,271 @@
+package config_test
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/config"
+)
+
+const sample = `
+apiVersion: config.platform.example.com/v1alpha1
+kind: OperatorConfig
+metrics:
+  bindAddress: ":9090"
+leaderElection:
+  enabled: true
+controllers:
+  tenant:
+    maxConcurrentReconciles: 4
+    rateLimiter:
+      qps: 50
+      burst: 500
+client:
+  qps: 100
+  burst: 200
+logging:
+  level: debug
+defaultDeletionPolicy: Retain
+reservedNamespaces: [kube-system, platform]
+featureGates:
+  Pruning: false
+  Hibernation: false
+network:
+  baseline: deny-ingress
+`
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg, err := config.Load(writeConfig(t, t.TempDir(), sample))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Validate()).To(Succeed())
+
+	g.Expect(cfg.Metrics.BindAddress).To(Equal(":9090"))
+	g.Expect(cfg.LeaderElection.Enabled).To(BeTrue())
+	g.Expect(cfg.Controllers.Tenant.MaxConcurrentReconciles).To(Equal(4))
+	g.Expect(cfg.Controllers.Tenant.RateLimiter.QPS).To(Equal(50.0))
+	g.Expect(cfg.Client).To(Equal(config.ClientConfig{QPS: 100, Burst: 200}))
+	g.Expect(cfg.DefaultDeletionPolicy).To(Equal("Retain"))
+	g.Expect(cfg.ReservedNamespaces).To(Equal([]string{"kube-system", "platform"}))
+	g.Expect(cfg.Network.Baseline).To(Equal(config.BaselineDenyIngress))
+
+	// Unset options keep their defaults
+	g.Expect(cfg.Health.BindAddress).To(Equal(":8081"))
+	g.Expect(cfg.LeaderElection.ID).To(Equal(config.Default().LeaderElection.ID))
+	g.Expect(cfg.Controllers.NetworkPolicy.MaxConcurrentReconciles).To(Equal(1))
+	g.Expect(cfg.Controllers.Tenant.RateLimiter.BaseDelay.Duration).To(Equal(5 * time.Millisecond))
+	g.Expect(cfg.Logging.Format).To(Equal(config.LogFormatJSON))
+	g.Expect(cfg.Network.DNS.Port).To(BeEquivalentTo(53))
+
+	_, err = config.Load(writeConfig(t, t.TempDir(), sample+"unknown: true\n"))
+	g.Expect(err).To(MatchError(ContainSubstring("unknown")))
+
+	_, err = config.Load(writeConfig(t, t.TempDir(), "apiVersion: v1\nkind: ConfigMap\n"))
+	g.Expect(err).To(MatchError(ContainSubstring(config.Kind)))
+}
+
+func TestPrecedence(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg, err := config.Load(writeConfig(t, t.TempDir(), sample))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	env := map[string]string{
+		"ENABLE_LEADER_ELECTION": "false",
+		"EXPIRY_WARNING":         "2h",
+		"LOG_DEV_MODE":           "true",
+	}
+	g.Expect(cfg.ApplyEnv(func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	})).To(Succeed())
+
+	var flags config.Flags
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Bind(fs)
+	g.Expect(fs.Parse([]string{"--log-level=warn", "--metrics-bind-address=0", "--feature-gates=Hibernation=true"})).To(Succeed())
+	flags.Apply(cfg)
+
+	g.Expect(cfg.LeaderElection.Enabled).To(BeFalse())
+	g.Expect(cfg.ExpiryWarning.Duration).To(Equal(2 * time.Hour))
+	g.Expect(cfg.Logging).To(Equal(config.LoggingConfig{Level: "warn", Format: config.LogFormatConsole}))
+	g.Expect(cfg.Metrics.BindAddress).To(Equal("0"))
+	g.Expect(cfg.FeatureGates).To(Equal(map[string]bool{"Pruning": false, "Hibernation": true}))
+
+	// config.Flags left unset do not override the file
+	g.Expect(cfg.Controllers.Tenant.MaxConcurrentReconciles).To(Equal(4))
+	g.Expect(cfg.Health.BindAddress).To(Equal(":8081"))
+	g.Expect(cfg.Validate()).To(Succeed())
+
+	g.Expect(fs.Parse([]string{"--feature-gates=Pruning"})).NotTo(Succeed())
+
+	g.Expect(config.Default().ApplyEnv(func(k string) (string, bool) {
+		return "soon", k == "EXPIRY_WARNING"
+	})).To(MatchError(ContainSubstring("EXPIRY_WARNING")))
+}
+
+func TestValidate(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(config.Default().Validate()).To(Succeed())
+
+	cfg := config.Default()
+	cfg.Metrics.BindAddress = "8080"
+	cfg.Controllers.Tenant.MaxConcurrentReconciles = 0
+	cfg.Controllers.NetworkPolicy.RateLimiter.MaxDelay.Duration = time.Millisecond
+	cfg.Controllers.TenantRequest.MaxConcurrentReconciles = 4
+	cfg.Client.Burst = 0
+	cfg.Logging.Level = "verbose"
+	cfg.DefaultDeletionPolicy = "Orphan"
+	cfg.ReservedNamespaces = []string{"Kube_System"}
+	cfg.Network.Baseline = "allow-all"
+	cfg.Network.DNS.Port = 0
+	cfg.Bootstrap.SourceNamespaces = []string{"Shared"}
+	cfg.PodSecurity.MinimumEnforce = "strict"
+	cfg.ExpiryWarning.Duration = -time.Hour
+	cfg.FeatureGates = map[string]bool{"Teleport": true}
+
+	err := cfg.Validate()
+	for _, field := range []string{
+		"metrics.bindAddress",
+		"controllers.tenant.maxConcurrentReconciles",
+		"controllers.networkPolicy.rateLimiter.maxDelay",
+		"controllers.tenantRequest.maxConcurrentReconciles",
+		"client.burst",
+		"logging.level",
+		"defaultDeletionPolicy",
+		"reservedNamespaces",
+		"network.baseline",
+		"network.dns.port",
+		"bootstrap.sourceNamespaces",
+		"podSecurity.minimumEnforce",
+		"expiryWarning",
+		"featureGates",
+	} {
+		g.Expect(err).To(MatchError(ContainSubstring(field)))
+	}
+}
+
+func TestReload(t *testing.T) {
+	g := NewWithT(t)
+
+	current := config.Default()
+	next := config.Default()
+	next.Metrics.BindAddress = ":9090"
+	next.Controllers.Tenant.MaxConcurrentReconciles = 8
+	next.Logging.Level = "debug"
+	next.ReservedNamespaces = []string{"platform"}
+
+	merged, ignored := current.Reload(next)
+	g.Expect(ignored).To(ConsistOf("metrics", "controllers"))
+	g.Expect(merged.Metrics).To(Equal(current.Metrics))
+	g.Expect(merged.Controllers).To(Equal(current.Controllers))
+	g.Expect(merged.Logging.Level).To(Equal("debug"))
+	g.Expect(merged.ReservedNamespaces).To(Equal([]string{"platform"}))
+}
+
+func TestSharding(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := config.Default()
+	g.Expect(cfg.Sharding.Enabled()).To(BeFalse())
+	g.Expect(cfg.Sharding.Owns("team-a", nil)).To(BeTrue())
+	g.Expect(cfg.LeaderElectionID()).To(Equal(cfg.LeaderElection.ID))
+
+	var flags config.Flags
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Bind(fs)
+	g.Expect(fs.Parse([]string{"--shards=4", "--shard=2"})).To(Succeed())
+	flags.Apply(cfg)
+
+	g.Expect(cfg.Validate()).To(Succeed())
+	g.Expect(cfg.LeaderElectionID()).To(Equal(cfg.LeaderElection.ID + "-shard-2"))
+
+	// Every tenant has exactly one shard, stable across calls
+	counts := map[int]int{}
+	for _, name := range []string{"team-a", "team-b", "team-c", "team-d", "team-e", "team-f", "team-g", "team-h"} {
+		shard := cfg.Sharding.ShardOf(name, nil)
+		g.Expect(shard).To(BeNumerically("<", 4))
+		g.Expect(cfg.Sharding.ShardOf(name, nil)).To(Equal(shard))
+		g.Expect(cfg.Sharding.Owns(name, nil)).To(Equal(shard == 2))
+		counts[shard]++
+	}
+	g.Expect(len(counts)).To(BeNumerically(">", 1))
+
+	// The label pins a tenant, unless it names no shard
+	g.Expect(cfg.Sharding.ShardOf("team-a", map[string]string{platformv1alpha1.ShardLabel: "3"})).To(Equal(3))
+	g.Expect(cfg.Sharding.ShardOf("team-a", map[string]string{platformv1alpha1.ShardLabel: "7"})).
+		To(Equal(cfg.Sharding.ShardOf("team-a", nil)))
+
+	cfg.Sharding.Shard = 4
+	g.Expect(cfg.Validate()).To(MatchError(ContainSubstring("sharding.shard")))
+	cfg.Sharding = config.ShardingConfig{}
+	g.Expect(cfg.Validate()).To(MatchError(ContainSubstring("sharding.shards")))
+}
+
+func TestWatcher(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	path := writeConfig(t, dir, sample)
+
+	initial, err := config.Load(path)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	store := config.NewStore(initial)
+	reloaded := make(chan *config.OperatorConfig, 10)
+	w := &config.Watcher{
+		Path:     path,
+		Store:    store,
+		Load:     func() (*config.OperatorConfig, error) { return config.Load(path) },
+		OnReload: func(cfg *config.OperatorConfig) { reloaded <- cfg },
+		Log:      logr.Discard(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = w.Start(ctx) }()
+
+	// Give the watcher time to register
+	g.Eventually(func() error {
+		writeConfig(t, dir, sample+"expiryWarning: 1h\n")
+		select {
+		case <-reloaded:
+			return nil
+		case <-time.After(100 * time.Millisecond):
+			return os.ErrDeadlineExceeded
+		}
+	}, 5*time.Second).Should(Succeed())
+	g.Expect(store.Get().ExpiryWarning.Duration).To(Equal(time.Hour))
+
+	// Invalid files are ignored
+	writeConfig(t, dir, sample+"expiryWarning: -1h\n")
+	g.Consistently(reloaded, 300*time.Millisecond).ShouldNot(Receive())
+	g.Expect(store.Get().ExpiryWarning.Duration).To(Equal(time.Hour))
+}
+
+func TestNilStore(t *testing.T) {
+	g := NewWithT(t)
+
+	var store *config.Store
+	g.Expect(store.Get()).To(Equal(config.Default()))
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"flag"
+	"maps"
+
+	"github.com/tngs/namespace-operator/features"
+)
+
+// -----------------------------------------------------------------------------
+// Flags are the command-line options. They override the configuration file
+// and the environment, but only when set on the command line.
+// -----------------------------------------------------------------------------
+type Flags struct {
+	// Path of the OperatorConfig file, watched for changes
+	ConfigFile string
+
+	metricsBindAddress      string
+	healthBindAddress       string
+	leaderElect             bool
+	leaderElectionID        string
+	leaderElectionNamespace string
+	logLevel                string
+	logFormat               string
+	shards                  int
+	shard                   int
+	featureGates            map[string]bool
+
+	fs *flag.FlagSet
+}
+
+// Bind registers the flags on fs
+func (f *Flags) Bind(fs *flag.FlagSet) {
+
+	defaults := Default()
+
+	fs.StringVar(&f.ConfigFile, "config", "",
+		"Path of the OperatorConfig file.")
+	fs.StringVar(&f.metricsBindAddress, "metrics-bind-address", defaults.Metrics.BindAddress,
+		`Address the metrics endpoint binds to, "0" to disable.`)
+	fs.StringVar(&f.healthBindAddress, "health-probe-bind-address", defaults.Health.BindAddress,
+		`Address the health probe endpoint binds to, "0" to disable.`)
+	fs.BoolVar(&f.leaderElect, "leader-elect", defaults.LeaderElection.Enabled,
+		"Enable leader election, so only one replica is active.")
+	fs.StringVar(&f.leaderElectionID, "leader-election-id", defaults.LeaderElection.ID,
+		"Name of the leader election Lease.")
+	fs.StringVar(&f.leaderElectionNamespace, "leader-election-namespace", defaults.LeaderElection.Namespace,
+		"Namespace of the leader election Lease, the operator namespace when empty.")
+	fs.IntVar(&f.shards, "shards", defaults.Sharding.Shards,
+		"Number of operator instances the Tenants are split between.")
+	fs.IntVar(&f.shard, "shard", defaults.Sharding.Shard,
+		"Shard reconciled by this instance, from 0 to shards-1.")
+	fs.StringVar(&f.logLevel, "log-level", defaults.Logging.Level,
+		"Log level: debug, info, warn or error.")
+	fs.StringVar(&f.logFormat, "log-format", defaults.Logging.Format,
+		"Log format: json or console.")
+	fs.Func("feature-gates",
+		"Comma separated Name=true|false pairs, merged over the featureGates of the configuration file.",
+		func(value string) error {
+			gates, err := features.Parse(value)
+			f.featureGates = gates
+			return err
+		})
+
+	f.fs = fs
+}
+
+// Apply sets the options given on the command line in cfg, after fs.Parse
+func (f *Flags) Apply(cfg *OperatorConfig) {
+
+	set := map[string]bool{}
+	f.fs.Visit(func(fl *flag.Flag) { set[fl.Name] = true })
+
+	if set["metrics-bind-address"] {
+		cfg.Metrics.BindAddress = f.metricsBindAddress
+	}
+	if set["health-probe-bind-address"] {
+		cfg.Health.BindAddress = f.healthBindAddress
+	}
+	if set["leader-elect"] {
+		cfg.LeaderElection.Enabled = f.leaderElect
+	}
+	if set["leader-election-id"] {
+		cfg.LeaderElection.ID = f.leaderElectionID
+	}
+	if set["leader-election-namespace"] {
+		cfg.LeaderElection.Namespace = f.leaderElectionNamespace
+	}
+	if set["shards"] {
+		cfg.Sharding.Shards = f.shards
+	}
+	if set["shard"] {
+		cfg.Sharding.Shard = f.shard
+	}
+	if set["log-level"] {
+		cfg.Logging.Level = f.logLevel
+	}
+	if set["log-format"] {
+		cfg.Logging.Format = f.logFormat
+	}
+	if set["feature-gates"] {
+		gates := maps.Clone(cfg.FeatureGates)
+		if gates == nil {
+			gates = map[string]bool{}
+		}
+		maps.Copy(gates, f.featureGates)
+		cfg.FeatureGates = gates
+	}
+}
@@ -0,0 +1,282 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/features"
+
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// -----------------------------------------------------------------------------
+// Load reads the configuration file at path over the defaults. An empty path
+// returns the defaults. Unknown fields are rejected.
+// -----------------------------------------------------------------------------
+func Load(path string) (*OperatorConfig, error) {
+
+	cfg := Default()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	if cfg.APIVersion != APIVersion || cfg.Kind != Kind {
+		return nil, fmt.Errorf("invalid configuration file %s: expected %s %s, got %s %s",
+			path, APIVersion, Kind, cfg.APIVersion, cfg.Kind)
+	}
+
+	return cfg, nil
+}
+
+// -----------------------------------------------------------------------------
+// ApplyEnv applies the environment variables supported before the
+// configuration file existed: LOG_DEV_MODE, ENABLE_LEADER_ELECTION,
+// LEADER_ELECTION_NAMESPACE, ENABLE_WEBHOOKS, EXPIRY_WARNING and AUDIT_LOG.
+// -----------------------------------------------------------------------------
+func (c *OperatorConfig) ApplyEnv(lookup func(string) (string, bool)) error {
+
+	var errs []error
+
+	parseBool := func(name string, apply func(bool)) {
+		if v, ok := lookup(name); ok && v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s: %w", name, err))
+				return
+			}
+			apply(b)
+		}
+	}
+
+	parseBool("LOG_DEV_MODE", func(dev bool) {
+		if dev {
+			c.Logging = LoggingConfig{Level: "debug", Format: LogFormatConsole}
+		}
+	})
+	parseBool("ENABLE_LEADER_ELECTION", func(b bool) { c.LeaderElection.Enabled = b })
+	parseBool("ENABLE_WEBHOOKS", func(b bool) { c.Webhook.Enabled = b })
+
+	if v, ok := lookup("LEADER_ELECTION_NAMESPACE"); ok && v != "" {
+		c.LeaderElection.Namespace = v
+	}
+	if v, ok := lookup("EXPIRY_WARNING"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid EXPIRY_WARNING: %w", err))
+		}
+		c.ExpiryWarning.Duration = d
+	}
+	if v, ok := lookup("AUDIT_LOG"); ok && v != "" {
+		c.AuditLog = v
+	}
+
+	return errors.Join(errs...)
+}
+
+// -----------------------------------------------------------------------------
+// Validate checks the configuration, reporting every invalid option.
+// -----------------------------------------------------------------------------
+func (c *OperatorConfig) Validate() error {
+
+	var errs []error
+	invalid := func(field string, value any, reason string) {
+		errs = append(errs, fmt.Errorf("%s: invalid value %v: %s", field, value, reason))
+	}
+
+	for field, addr := range map[string]string{
+		"metrics.bindAddress": c.Metrics.BindAddress,
+		"health.bindAddress":  c.Health.BindAddress,
+	} {
+		if addr == "0" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			invalid(field, strconv.Quote(addr), `must be host:port or "0"`)
+		}
+	}
+
+	if c.Health.StallTimeout.Duration < 0 {
+		invalid("health.stallTimeout", c.Health.StallTimeout.Duration, "must not be negative")
+	}
+
+	if c.Webhook.Port < 1 || c.Webhook.Port > 65535 {
+		invalid("webhook.port", c.Webhook.Port, "must be a port number")
+	}
+	if c.LeaderElection.ID == "" {
+		invalid("leaderElection.id", `""`, "must not be empty")
+	}
+
+	for field, cc := range map[string]ControllerConfig{
+		"controllers.tenant":        c.Controllers.Tenant,
+		"controllers.networkPolicy": c.Controllers.NetworkPolicy,
+		"controllers.tenantRequest": c.Controllers.TenantRequest,
+	} {
+		if cc.MaxConcurrentReconciles < 1 {
+			invalid(field+".maxConcurrentReconciles", cc.MaxConcurrentReconciles, "must be at least 1")
+		}
+
+		rl := cc.RateLimiter
+		if rl.BaseDelay.Duration <= 0 {
+			invalid(field+".rateLimiter.baseDelay", rl.BaseDelay.Duration, "must be positive")
+		}
+		if rl.MaxDelay.Duration < rl.BaseDelay.Duration {
+			invalid(field+".rateLimiter.maxDelay", rl.MaxDelay.Duration, "must not be below baseDelay")
+		}
+		if rl.QPS <= 0 {
+			invalid(field+".rateLimiter.qps", rl.QPS, "must be positive")
+		}
+		if rl.Burst < 1 {
+			invalid(field+".rateLimiter.burst", rl.Burst, "must be at least 1")
+		}
+	}
+
+	// One worker, so two requests of a group never pass the cap together
+	if c.Controllers.TenantRequest.MaxConcurrentReconciles > 1 {
+		invalid("controllers.tenantRequest.maxConcurrentReconciles",
+			c.Controllers.TenantRequest.MaxConcurrentReconciles, "must be 1")
+	}
+
+	if c.Client.QPS <= 0 {
+		invalid("client.qps", c.Client.QPS, "must be positive")
+	}
+	if c.Client.Burst < 1 {
+		invalid("client.burst", c.Client.Burst, "must be at least 1")
+	}
+
+	if c.Sharding.Shards < 1 {
+		invalid("sharding.shards", c.Sharding.Shards, "must be at least 1")
+	}
+	if c.Sharding.Shard < 0 || c.Sharding.Shard >= max(c.Sharding.Shards, 1) {
+		invalid("sharding.shard", c.Sharding.Shard, "must be from 0 to shards-1")
+	}
+
+	if c.SyncPeriod.Duration <= 0 {
+		invalid("syncPeriod", c.SyncPeriod.Duration, "must be positive")
+	}
+
+	if _, err := features.New(c.FeatureGates); err != nil {
+		errs = append(errs, fmt.Errorf("featureGates: %w", err))
+	}
+
+	if _, err := zapcore.ParseLevel(c.Logging.Level); err != nil {
+		invalid("logging.level", strconv.Quote(c.Logging.Level), "must be debug, info, warn or error")
+	}
+	if c.Logging.Format != LogFormatJSON && c.Logging.Format != LogFormatConsole {
+		invalid("logging.format", strconv.Quote(c.Logging.Format), "must be json or console")
+	}
+
+	if !slices.Contains(platformv1alpha1.DeletionPolicies, c.DefaultDeletionPolicy) {
+		invalid("defaultDeletionPolicy", strconv.Quote(c.DefaultDeletionPolicy), "must be Delete or Retain")
+	}
+
+	for _, ns := range c.ReservedNamespaces {
+		if msgs := validation.IsDNS1123Label(ns); len(msgs) > 0 {
+			invalid("reservedNamespaces", strconv.Quote(ns), msgs[0])
+		}
+	}
+
+	switch c.Network.Baseline {
+	case BaselineDenyAll, BaselineDenyIngress, BaselineNone:
+	default:
+		invalid("network.baseline", strconv.Quote(c.Network.Baseline), "must be deny-all, deny-ingress or none")
+	}
+	if msgs := validation.IsDNS1123Label(c.Network.DNS.Namespace); len(msgs) > 0 {
+		invalid("network.dns.namespace", strconv.Quote(c.Network.DNS.Namespace), msgs[0])
+	}
+	if c.Network.DNS.Port < 1 || c.Network.DNS.Port > 65535 {
+		invalid("network.dns.port", c.Network.DNS.Port, "must be a port number")
+	}
+
+	for _, ns := range c.Bootstrap.SourceNamespaces {
+		if msgs := validation.IsDNS1123Label(ns); len(msgs) > 0 {
+			invalid("bootstrap.sourceNamespaces", strconv.Quote(ns), msgs[0])
+		}
+	}
+
+	for _, role := range c.Access.AllowedRoles {
+		if role == "" {
+			invalid("access.allowedRoles", `""`, "must not be empty")
+		}
+	}
+
+	switch c.PodSecurity.MinimumEnforce {
+	case platformv1alpha1.PodSecurityLevelPrivileged,
+		platformv1alpha1.PodSecurityLevelBaseline,
+		platformv1alpha1.PodSecurityLevelRestricted:
+	default:
+		invalid("podSecurity.minimumEnforce", strconv.Quote(c.PodSecurity.MinimumEnforce),
+			"must be privileged, baseline or restricted")
+	}
+
+	if c.ExpiryWarning.Duration < 0 {
+		invalid("expiryWarning", c.ExpiryWarning.Duration, "must not be negative")
+	}
+
+	return errors.Join(errs...)
+}
+
+// IsReserved reports whether a Tenant may not manage namespace
+func (c *OperatorConfig) IsReserved(namespace string) bool {
+	return slices.Contains(c.ReservedNamespaces, namespace)
+}
+
+// -----------------------------------------------------------------------------
+// Reload returns next with the startup options of c, which only apply after
+// a restart, and the names of the startup options next tries to change.
+// -----------------------------------------------------------------------------
+func (c *OperatorConfig) Reload(next *OperatorConfig) (*OperatorConfig, []string) {
+
+	merged := *next
+	var ignored []string
+
+	keep := func(name string, current, requested any) {
+		if !reflect.DeepEqual(current, requested) {
+			ignored = append(ignored, name)
+		}
+	}
+
+	keep("metrics", c.Metrics, next.Metrics)
+	keep("health", c.Health, next.Health)
+	keep("webhook", c.Webhook, next.Webhook)
+	keep("leaderElection", c.LeaderElection, next.LeaderElection)
+	keep("controllers", c.Controllers, next.Controllers)
+	keep("client", c.Client, next.Client)
+	keep("sharding", c.Sharding, next.Sharding)
+	keep("bootstrap", c.Bootstrap, next.Bootstrap)
+	keep("syncPeriod", c.SyncPeriod, next.SyncPeriod)
+	keep("featureGates", c.FeatureGates, next.FeatureGates)
+	keep("logging.format", c.Logging.Format, next.Logging.Format)
+	keep("auditLog", c.AuditLog, next.AuditLog)
+
+	merged.Metrics = c.Metrics
+	merged.Health = c.Health
+	merged.Webhook = c.Webhook
+	merged.LeaderElection = c.LeaderElection
+	merged.Controllers = c.Controllers
+	merged.Client = c.Client
+	merged.Sharding = c.Sharding
+	merged.Bootstrap = c.Bootstrap
+	merged.SyncPeriod = c.SyncPeriod
+	merged.FeatureGates = c.FeatureGates
+	merged.Logging.Format = c.Logging.Format
+	merged.AuditLog = c.AuditLog
+
+	return &merged, ignored
+}
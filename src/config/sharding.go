@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// ShardingConfig splits the Tenants between several operator instances. Each
+// instance runs with its own shard index and leader election Lease, and only
+// reconciles the Tenants of its shard: the one set by the
+// platform.example.com/shard label, or else a hash of the Tenant name.
+// -----------------------------------------------------------------------------
+type ShardingConfig struct {
+	// Number of shards, 1 when not sharded
+	Shards int `json:"shards,omitempty"`
+
+	// Shard of this instance, from 0 to shards-1
+	Shard int `json:"shard,omitempty"`
+}
+
+// Enabled reports whether Tenants are split between several instances
+func (s ShardingConfig) Enabled() bool {
+	return s.Shards > 1
+}
+
+// ShardOf returns the shard of the named Tenant with the given labels. An
+// invalid or out of range label is ignored.
+func (s ShardingConfig) ShardOf(tenant string, labels map[string]string) int {
+	if !s.Enabled() {
+		return 0
+	}
+
+	if v, ok := labels[platformv1alpha1.ShardLabel]; ok {
+		if shard, err := ParseShard(v, s.Shards); err == nil {
+			return shard
+		}
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(tenant))
+	return int(h.Sum32() % uint32(s.Shards))
+}
+
+// Owns reports whether this instance reconciles the named Tenant
+func (s ShardingConfig) Owns(tenant string, labels map[string]string) bool {
+	return s.ShardOf(tenant, labels) == s.Shard
+}
+
+// ParseShard parses a shard label value, which must be below shards
+func ParseShard(value string, shards int) (int, error) {
+	shard, err := strconv.Atoi(value)
+	if err != nil || shard < 0 {
+		return 0, fmt.Errorf("must be a shard number")
+	}
+	if shard >= max(shards, 1) {
+		return 0, fmt.Errorf("must be below the number of shards (%d)", max(shards, 1))
+	}
+	return shard, nil
+}
+
+// -----------------------------------------------------------------------------
+// LeaderElectionID returns the name of the leader election Lease: one Lease
+// per shard, so each shard has its own active leader.
+// -----------------------------------------------------------------------------
+func (c *OperatorConfig) LeaderElectionID() string {
+	if !c.Sharding.Enabled() {
+		return c.LeaderElection.ID
+	}
+	return fmt.Sprintf("%s-shard-%d", c.LeaderElection.ID, c.Sharding.Shard)
+}
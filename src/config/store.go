@@ -0,0 +1,122 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+)
+
+// -----------------------------------------------------------------------------
+// Store holds the current configuration, shared by the controllers and the
+// webhooks. A nil Store returns the defaults.
+// -----------------------------------------------------------------------------
+type Store struct {
+	current atomic.Pointer[OperatorConfig]
+}
+
+// NewStore returns a Store holding cfg
+func NewStore(cfg *OperatorConfig) *Store {
+	s := &Store{}
+	s.Set(cfg)
+	return s
+}
+
+// Get returns the current configuration, which must not be modified
+func (s *Store) Get() *OperatorConfig {
+	if s == nil {
+		return Default()
+	}
+	if cfg := s.current.Load(); cfg != nil {
+		return cfg
+	}
+	return Default()
+}
+
+// Set replaces the current configuration
+func (s *Store) Set(cfg *OperatorConfig) {
+	s.current.Store(cfg)
+}
+
+// -----------------------------------------------------------------------------
+// Watcher reloads the configuration file when it changes. It watches the
+// parent directory, so that the atomic symlink swap of a mounted ConfigMap is
+// seen. Invalid files are logged and ignored; changes to startup options are
+// logged and ignored until the next restart.
+// -----------------------------------------------------------------------------
+type Watcher struct {
+	Path  string
+	Store *Store
+
+	// Load reads the configuration, with its environment and flag overrides
+	Load func() (*OperatorConfig, error)
+
+	// OnReload is called with every new configuration, if set
+	OnReload func(*OperatorConfig)
+
+	Log logr.Logger
+}
+
+// NeedLeaderElection is false: every replica reloads its configuration
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start watches the file until ctx is done
+func (w *Watcher) Start(ctx context.Context) error {
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fsw.Close()
+
+	if err := fsw.Add(filepath.Dir(w.Path)); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-fsw.Errors:
+			w.Log.Error(err, "configuration watch error", "path", w.Path)
+		case ev := <-fsw.Events:
+			if ev.Has(fsnotify.Chmod) {
+				continue
+			}
+			w.reload()
+		}
+	}
+}
+
+// reload loads, validates and publishes the configuration if it changed
+func (w *Watcher) reload() {
+
+	next, err := w.Load()
+	if err == nil {
+		err = next.Validate()
+	}
+	if err != nil {
+		w.Log.Error(err, "ignoring invalid configuration", "path", w.Path)
+		return
+	}
+
+	current := w.Store.Get()
+	merged, ignored := current.Reload(next)
+	if len(ignored) > 0 {
+		w.Log.Info("configuration options changed, restart to apply", "options", ignored)
+	}
+	if reflect.DeepEqual(current, merged) {
+		return
+	}
+
+	w.Store.Set(merged)
+	if w.OnReload != nil {
+		w.OnReload(merged)
+	}
+	w.Log.Info("configuration reloaded", "path", w.Path)
+}
@@ -0,0 +1,279 @@
+// Package config holds the operator configuration: a versioned
+// OperatorConfig file, overridden by environment variables and flags.
+package config
+
+import (
+	"time"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Version and kind of the configuration file
+const (
+	APIVersion = "config.platform.example.com/v1alpha1"
+	Kind       = "OperatorConfig"
+)
+
+// Network baselines applied to tenants without custom network rules
+const (
+	// BaselineDenyAll denies all ingress and egress except DNS
+	BaselineDenyAll = "deny-all"
+
+	// BaselineDenyIngress only denies ingress
+	BaselineDenyIngress = "deny-ingress"
+
+	// BaselineNone applies no policy
+	BaselineNone = "none"
+)
+
+// Log formats
+const (
+	LogFormatJSON    = "json"
+	LogFormatConsole = "console"
+)
+
+// -----------------------------------------------------------------------------
+// OperatorConfig is the content of the configuration file. Startup options
+// (bind addresses, leader election, controllers, client, sharding, cache,
+// bootstrap sources, feature gates, log format, audit log) need a restart; the others are reloaded when
+// the file changes.
+// -----------------------------------------------------------------------------
+type OperatorConfig struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	Metrics        EndpointConfig       `json:"metrics,omitempty"`
+	Health         HealthConfig         `json:"health,omitempty"`
+	Webhook        WebhookConfig        `json:"webhook,omitempty"`
+	LeaderElection LeaderElectionConfig `json:"leaderElection,omitempty"`
+	Controllers    ControllersConfig    `json:"controllers,omitempty"`
+	Client         ClientConfig         `json:"client,omitempty"`
+	Sharding       ShardingConfig       `json:"sharding,omitempty"`
+	Bootstrap      BootstrapConfig      `json:"bootstrap,omitempty"`
+
+	// Period after which every watched object is reconciled again
+	SyncPeriod metav1.Duration `json:"syncPeriod,omitempty"`
+
+	// Feature gates turned on or off, by name, see the features package
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	Logging LoggingConfig `json:"logging,omitempty"`
+
+	// Audit log destination: stdout or a file path, disabled when empty
+	AuditLog string `json:"auditLog,omitempty"`
+
+	// Reloaded options
+
+	// Deletion policy of Tenants that do not set spec.deletionPolicy
+	DefaultDeletionPolicy string `json:"defaultDeletionPolicy,omitempty"`
+
+	// Namespaces a Tenant can never manage
+	ReservedNamespaces []string `json:"reservedNamespaces,omitempty"`
+
+	Network NetworkConfig `json:"network,omitempty"`
+
+	Access AccessConfig `json:"access,omitempty"`
+
+	PodSecurity PodSecurityConfig `json:"podSecurity,omitempty"`
+
+	// How long before expiring a Tenant is reported as expiring soon
+	ExpiryWarning metav1.Duration `json:"expiryWarning,omitempty"`
+}
+
+// EndpointConfig configures an HTTP endpoint served by the operator
+type EndpointConfig struct {
+	// host:port to listen on, "0" to disable
+	BindAddress string `json:"bindAddress,omitempty"`
+}
+
+// HealthConfig configures the health probe endpoint and its checks
+type HealthConfig struct {
+	// host:port to listen on, "0" to disable
+	BindAddress string `json:"bindAddress,omitempty"`
+
+	// How long a controller may have work queued without completing a
+	// reconcile before liveness fails, 0 to disable the check
+	StallTimeout metav1.Duration `json:"stallTimeout,omitempty"`
+}
+
+// WebhookConfig configures the admission webhook server
+type WebhookConfig struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Port    int    `json:"port,omitempty"`
+	CertDir string `json:"certDir,omitempty"`
+}
+
+// LeaderElectionConfig configures leader election between replicas
+type LeaderElectionConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Name of the Lease
+	ID string `json:"id,omitempty"`
+
+	// Namespace of the Lease, defaults to the operator namespace
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ControllersConfig configures each controller
+type ControllersConfig struct {
+	Tenant        ControllerConfig `json:"tenant,omitempty"`
+	NetworkPolicy ControllerConfig `json:"networkPolicy,omitempty"`
+
+	// Always one worker: the per-group tenant cap is checked, then the
+	// Tenant created, by one reconcile at a time
+	TenantRequest ControllerConfig `json:"tenantRequest,omitempty"`
+}
+
+// ControllerConfig configures one controller
+type ControllerConfig struct {
+	// Number of objects reconciled in parallel
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+
+	RateLimiter RateLimiterConfig `json:"rateLimiter,omitempty"`
+}
+
+// -----------------------------------------------------------------------------
+// RateLimiterConfig paces the requeues of a controller: each object backs off
+// exponentially after a failure, and all objects share a token bucket.
+// -----------------------------------------------------------------------------
+type RateLimiterConfig struct {
+	// First retry delay of a failing object, doubled on each failure
+	BaseDelay metav1.Duration `json:"baseDelay,omitempty"`
+
+	// Longest retry delay of a failing object
+	MaxDelay metav1.Duration `json:"maxDelay,omitempty"`
+
+	// Requeues per second and burst of the shared token bucket
+	QPS   float64 `json:"qps,omitempty"`
+	Burst int     `json:"burst,omitempty"`
+}
+
+// ClientConfig limits the requests of the operator to the API server
+type ClientConfig struct {
+	QPS   float32 `json:"qps,omitempty"`
+	Burst int     `json:"burst,omitempty"`
+}
+
+// LoggingConfig configures the debug logs (not the audit log)
+type LoggingConfig struct {
+	// debug, info, warn or error; reloaded
+	Level string `json:"level,omitempty"`
+
+	// json or console
+	Format string `json:"format,omitempty"`
+}
+
+// NetworkConfig configures the NetworkPolicies of tenants without custom rules
+type NetworkConfig struct {
+	// deny-all, deny-ingress or none
+	Baseline string `json:"baseline,omitempty"`
+
+	DNS DNSConfig `json:"dns,omitempty"`
+}
+
+// BootstrapConfig configures the objects profiles copy into tenant namespaces
+type BootstrapConfig struct {
+	// Namespaces holding the Secrets and ConfigMaps copied by profiles. Only
+	// these are cached and watched, so copies follow changes to their
+	// source; sources elsewhere are still copied, and refreshed on resync.
+	SourceNamespaces []string `json:"sourceNamespaces,omitempty"`
+}
+
+// AccessConfig limits the roles tenants and profiles may grant
+type AccessConfig struct {
+	// ClusterRoles the operator may bind in tenant namespaces. The operator
+	// ClusterRole only allows binding these (see the chart access values).
+	AllowedRoles []string `json:"allowedRoles,omitempty"`
+}
+
+// PodSecurityConfig holds the operator-wide Pod Security settings
+type PodSecurityConfig struct {
+	// Lowest enforce level of tenants without a profile: privileged,
+	// baseline or restricted
+	MinimumEnforce string `json:"minimumEnforce,omitempty"`
+}
+
+// DNSConfig is the DNS service tenant pods may always reach under deny-all
+type DNSConfig struct {
+	Namespace string `json:"namespace,omitempty"`
+
+	// Labels of the DNS pods, any pod of the namespace when empty
+	PodSelector map[string]string `json:"podSelector,omitempty"`
+
+	Port int32 `json:"port,omitempty"`
+}
+
+// -----------------------------------------------------------------------------
+// Default returns the configuration used when no file, environment variable
+// or flag sets an option.
+// -----------------------------------------------------------------------------
+func Default() *OperatorConfig {
+	return &OperatorConfig{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+		Metrics:    EndpointConfig{BindAddress: ":8080"},
+		Health: HealthConfig{
+			BindAddress:  ":8081",
+			StallTimeout: metav1.Duration{Duration: 10 * time.Minute},
+		},
+		Webhook: WebhookConfig{
+			Port:    9443,
+			CertDir: "/tmp/k8s-webhook-server/serving-certs",
+		},
+		LeaderElection: LeaderElectionConfig{
+			ID: "namespace-operator.platform.example.com",
+		},
+		Controllers: ControllersConfig{
+			Tenant:        defaultController(),
+			NetworkPolicy: defaultController(),
+			TenantRequest: defaultController(),
+		},
+		Client: ClientConfig{
+			QPS:   20,
+			Burst: 30,
+		},
+		Sharding:   ShardingConfig{Shards: 1},
+		SyncPeriod: metav1.Duration{Duration: 10 * time.Hour},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: LogFormatJSON,
+		},
+		DefaultDeletionPolicy: platformv1alpha1.DeletionPolicyDelete,
+		ReservedNamespaces: []string{
+			"default",
+			"kube-node-lease",
+			"kube-public",
+			"kube-system",
+		},
+		Network: NetworkConfig{
+			Baseline: BaselineDenyAll,
+			DNS: DNSConfig{
+				Namespace: "kube-system",
+				Port:      53,
+			},
+		},
+		Access: AccessConfig{
+			AllowedRoles: []string{"admin", "edit", "view"},
+		},
+		PodSecurity: PodSecurityConfig{
+			MinimumEnforce: platformv1alpha1.PodSecurityLevelBaseline,
+		},
+		ExpiryWarning: metav1.Duration{Duration: 24 * time.Hour},
+	}
+}
+
+// defaultController matches the controller-runtime defaults: one worker and
+// the client-go default rate limiter
+func defaultController() ControllerConfig {
+	return ControllerConfig{
+		MaxConcurrentReconciles: 1,
+		RateLimiter: RateLimiterConfig{
+			BaseDelay: metav1.Duration{Duration: 5 * time.Millisecond},
+			MaxDelay:  metav1.Duration{Duration: 1000 * time.Second},
+			QPS:       10,
+			Burst:     100,
+		},
+	}
+}
@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/audit"
+	"github.com/tngs/namespace-operator/features"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// roleBindingPrefix prefixes the RoleBindings generated from access bindings
+const roleBindingPrefix = "tenant-"
+
+// -----------------------------------------------------------------------------
+// buildRoleBindings turns the profile default and tenant access bindings into
+// one RoleBinding per role. Subjects granted the same role are merged and
+// sorted so the generated objects are stable across reconciles.
+// -----------------------------------------------------------------------------
+func buildRoleBindings(
+	tenant *platformv1alpha1.Tenant,
+	profile *platformv1alpha1.TenantProfile,
+) []*rbacv1.RoleBinding {
+
+	var bindings []platformv1alpha1.AccessBinding
+	if profile != nil {
+		bindings = append(bindings, profile.Spec.Access...)
+	}
+	bindings = append(bindings, tenant.Spec.Access...)
+
+	subjectsByRole := map[string]map[rbacv1.Subject]struct{}{}
+	for _, b := range bindings {
+		subjects, ok := subjectsByRole[b.Role]
+		if !ok {
+			subjects = map[rbacv1.Subject]struct{}{}
+			subjectsByRole[b.Role] = subjects
+		}
+
+		for _, user := range b.Users {
+			subjects[rbacv1.Subject{
+				Kind:     rbacv1.UserKind,
+				APIGroup: rbacv1.GroupName,
+				Name:     user,
+			}] = struct{}{}
+		}
+		for _, group := range b.Groups {
+			subjects[rbacv1.Subject{
+				Kind:     rbacv1.GroupKind,
+				APIGroup: rbacv1.GroupName,
+				Name:     group,
+			}] = struct{}{}
+		}
+		for _, sa := range b.ServiceAccounts {
+			namespace := sa.Namespace
+			if namespace == "" {
+				namespace = tenant.Spec.Namespace
+			}
+			subjects[rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      sa.Name,
+				Namespace: namespace,
+			}] = struct{}{}
+		}
+	}
+
+	roles := make([]string, 0, len(subjectsByRole))
+	for role := range subjectsByRole {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	roleBindings := make([]*rbacv1.RoleBinding, 0, len(roles))
+	for _, role := range roles {
+
+		subjects := make([]rbacv1.Subject, 0, len(subjectsByRole[role]))
+		for s := range subjectsByRole[role] {
+			subjects = append(subjects, s)
+		}
+		sort.Slice(subjects, func(i, j int) bool {
+			if subjects[i].Kind != subjects[j].Kind {
+				return subjects[i].Kind < subjects[j].Kind
+			}
+			if subjects[i].Namespace != subjects[j].Namespace {
+				return subjects[i].Namespace < subjects[j].Namespace
+			}
+			return subjects[i].Name < subjects[j].Name
+		})
+
+		roleBindings = append(roleBindings, &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      roleBindingPrefix + role,
+				Namespace: tenant.Spec.Namespace,
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     role,
+			},
+			Subjects: subjects,
+		})
+	}
+
+	return roleBindings
+}
+
+// -----------------------------------------------------------------------------
+// reconcileAccess applies the RoleBindings for the tenant and prunes the ones
+// no longer requested. Roles outside allowedRoles are not bound (and pruned
+// when bound before). It returns the names of the managed RoleBindings.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) reconcileAccess(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	profile *platformv1alpha1.TenantProfile,
+	allowedRoles []string,
+	applied bool,
+) ([]string, error) {
+
+	desired := sets.New[string]()
+
+	for _, rb := range buildRoleBindings(tenant, profile) {
+
+		if !slices.Contains(allowedRoles, rb.RoleRef.Name) {
+			events(r.Recorder).Eventf(
+				tenant,
+				corev1.EventTypeWarning,
+				EventReasonRoleNotAllowed,
+				"ClusterRole %s is not in access.allowedRoles, not binding it",
+				rb.RoleRef.Name,
+			)
+			continue
+		}
+
+		rb.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("RoleBinding"))
+		rb.Labels = objectLabels(tenant, struct {
+			RoleRef  rbacv1.RoleRef   `json:"roleRef"`
+			Subjects []rbacv1.Subject `json:"subjects"`
+		}{rb.RoleRef, rb.Subjects})
+
+		if err := controllerutil.SetControllerReference(tenant, rb, r.Scheme); err != nil {
+			return nil, err
+		}
+
+		res, err := applyObject(ctx, r.Client, rb)
+		if err != nil {
+			return nil, err
+		}
+		if res.drifted(rb.Labels[SpecHashLabelKey], applied) {
+			recordDrift(r.Recorder, tenant, "RoleBinding", rb.Namespace, rb.Name)
+		}
+
+		desired.Insert(rb.Name)
+	}
+
+	// -------------------------------------------------------------------------
+	// Prune RoleBindings that are no longer requested
+	// -------------------------------------------------------------------------
+	if !r.Features.Enabled(features.Pruning) {
+		return sets.List(desired), nil
+	}
+
+	var existing rbacv1.RoleBindingList
+	if err := r.List(
+		ctx,
+		&existing,
+		client.InNamespace(tenant.Spec.Namespace),
+		client.MatchingLabels{
+			ManagedByLabelKey: ManagedByLabelValue,
+			TenantLabelKey:    tenant.Name,
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	for i := range existing.Items {
+		rb := &existing.Items[i]
+		if desired.Has(rb.Name) {
+			continue
+		}
+		if err := r.Delete(ctx, rb); client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+		audit.Record(ctx, audit.Delete, AuditReasonPruned, rb, nil)
+	}
+
+	return sets.List(desired), nil
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/config"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuildRoleBindings(t *testing.T) {
+	g := NewWithT(t)
+
+	profile := &platformv1alpha1.TenantProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "standard"},
+		Spec: platformv1alpha1.TenantProfileSpec{
+			Access: []platformv1alpha1.AccessBinding{
+				{Role: "admin", Groups: []string{"platform-oncall"}},
+			},
+		},
+	}
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec: platformv1alpha1.TenantSpec{
+			Namespace: "team-a",
+			Access: []platformv1alpha1.AccessBinding{
+				{Role: "admin", Users: []string{"alice@example.com"}},
+				{
+					Role:            "view",
+					Groups:          []string{"auditors"},
+					ServiceAccounts: []platformv1alpha1.ServiceAccountReference{{Name: "ci"}},
+				},
+			},
+		},
+	}
+
+	rbs := buildRoleBindings(tenant, profile)
+	g.Expect(rbs).To(HaveLen(2))
+
+	// Profile and tenant subjects for the same role are merged
+	g.Expect(rbs[0].Name).To(Equal("tenant-admin"))
+	g.Expect(rbs[0].RoleRef.Name).To(Equal("admin"))
+	g.Expect(rbs[0].Subjects).To(ConsistOf(
+		rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "platform-oncall"},
+		rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "alice@example.com"},
+	))
+
+	// ServiceAccounts default to the tenant namespace
+	g.Expect(rbs[1].Name).To(Equal("tenant-view"))
+	g.Expect(rbs[1].Subjects).To(ContainElement(
+		rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci", Namespace: "team-a"},
+	))
+}
+
+func TestReconcileAccess_AllowedRoles(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", UID: "tenant-uid"},
+		Spec: platformv1alpha1.TenantSpec{
+			Namespace: "team-a",
+			Access: []platformv1alpha1.AccessBinding{
+				{Role: "edit", Groups: []string{"team-a"}},
+				{Role: "cluster-admin", Groups: []string{"team-a"}},
+			},
+		},
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	r := &TenantReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+
+	names, err := r.reconcileAccess(ctx, tenant, nil, config.Default().Access.AllowedRoles, false)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(names).To(Equal([]string{"tenant-edit"}))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring(EventReasonRoleNotAllowed)))
+}
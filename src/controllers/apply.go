@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/tngs/namespace-operator/audit"
+	"github.com/tngs/namespace-operator/tracing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// -----------------------------------------------------------------------------
+// applyResult describes the live object as it was before a server-side apply,
+// and whether the apply changed it.
+// -----------------------------------------------------------------------------
+type applyResult struct {
+	existed  bool
+	prevHash string
+	changed  bool
+}
+
+// -----------------------------------------------------------------------------
+// applyObject server-side applies obj with the operator field manager.
+// Only the fields set on obj are owned by the operator, so labels, annotations
+// or other fields added by other tools (Argo CD, admission controllers, ...)
+// are left alone. obj must have its GroupVersionKind set.
+// -----------------------------------------------------------------------------
+func applyObject(
+	ctx context.Context,
+	c client.Client,
+	obj client.Object,
+) (res applyResult, err error) {
+
+	// Read before the patch, which may clear the type meta of typed objects
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+
+	ctx, span := tracing.Start(ctx, "Apply "+kind,
+		tracing.KindKey.String(kind),
+		tracing.NamespaceKey.String(obj.GetNamespace()),
+		tracing.NameKey.String(obj.GetName()),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	live := obj.DeepCopyObject().(client.Object)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), live); err == nil {
+		res.existed = true
+		res.prevHash = live.GetLabels()[SpecHashLabelKey]
+	} else if !apierrors.IsNotFound(err) {
+		return res, err
+	}
+
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+
+	if err := c.Patch(
+		ctx,
+		obj,
+		client.Apply,
+		client.FieldOwner(FieldOwner),
+		client.ForceOwnership,
+	); err != nil {
+		return res, err
+	}
+	ChildApplies.WithLabelValues(kind).Inc()
+
+	// The API server does not bump the resourceVersion of a no-op apply
+	res.changed = !res.existed || obj.GetResourceVersion() != live.GetResourceVersion()
+
+	if res.changed {
+		switch {
+		case !res.existed:
+			audit.Record(ctx, audit.Create, AuditReasonCreated, nil, obj)
+		case res.prevHash != "" && res.prevHash == obj.GetLabels()[SpecHashLabelKey]:
+			audit.Record(ctx, audit.Update, EventReasonDriftCorrected, live, obj)
+		default:
+			audit.Record(ctx, audit.Update, AuditReasonUpdated, live, obj)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,353 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/audit"
+	"github.com/tngs/namespace-operator/features"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// defaultServiceAccount is patched with the profile imagePullSecrets
+	defaultServiceAccount = "default"
+
+	// profileBootstrapSourceIndex indexes TenantProfiles by the objects they
+	// copy, as "<kind>/<namespace>/<name>"
+	profileBootstrapSourceIndex = "spec.bootstrap.copy"
+)
+
+// bootstrapSourceKey identifies a copied object in profileBootstrapSourceIndex.
+func bootstrapSourceKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// -----------------------------------------------------------------------------
+// buildBootstrapObjects returns the Secrets and ConfigMaps the profile
+// bootstrap section asks for in the tenant namespace: copies of the source
+// objects and rendered ConfigMap templates.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) buildBootstrapObjects(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	profile *platformv1alpha1.TenantProfile,
+) ([]client.Object, error) {
+
+	bootstrap := profile.Spec.Bootstrap
+	nsName := tenant.Spec.Namespace
+
+	var objects []client.Object
+
+	for _, c := range bootstrap.Copy {
+
+		// Never copy an object onto itself
+		if c.SourceNamespace == nsName && c.TargetObjectName() == c.Name {
+			continue
+		}
+
+		source := client.ObjectKey{Namespace: c.SourceNamespace, Name: c.Name}
+		meta := metav1.ObjectMeta{
+			Name:      c.TargetObjectName(),
+			Namespace: nsName,
+			Annotations: map[string]string{
+				SourceAnnotationKey: source.String(),
+			},
+		}
+
+		switch c.Kind {
+		case platformv1alpha1.BootstrapKindSecret:
+			src := &corev1.Secret{}
+			if err := r.reader().Get(ctx, source, src); err != nil {
+				return nil, fmt.Errorf("bootstrap Secret %s: %w", source, err)
+			}
+
+			// The hash identifies the source version, never the data: an
+			// unsalted hash of a secret in a label is readable by anyone
+			// allowed to list the Secrets
+			secret := &corev1.Secret{ObjectMeta: meta, Type: src.Type, Data: src.Data}
+			secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+			secret.Labels = objectLabels(tenant, struct {
+				UID             types.UID `json:"uid"`
+				ResourceVersion string    `json:"resourceVersion"`
+			}{src.UID, src.ResourceVersion})
+			objects = append(objects, secret)
+
+		case platformv1alpha1.BootstrapKindConfigMap:
+			src := &corev1.ConfigMap{}
+			if err := r.reader().Get(ctx, source, src); err != nil {
+				return nil, fmt.Errorf("bootstrap ConfigMap %s: %w", source, err)
+			}
+
+			cm := &corev1.ConfigMap{ObjectMeta: meta, Data: src.Data, BinaryData: src.BinaryData}
+			cm.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+			cm.Labels = objectLabels(tenant, struct {
+				Data       map[string]string `json:"data"`
+				BinaryData map[string][]byte `json:"binaryData"`
+			}{cm.Data, cm.BinaryData})
+			objects = append(objects, cm)
+
+		default:
+			return nil, fmt.Errorf("bootstrap copy %s: unsupported kind %q", c.Name, c.Kind)
+		}
+	}
+
+	data := newTemplateData(tenant, profile)
+
+	for _, t := range bootstrap.ConfigMaps {
+
+		rendered := make(map[string]string, len(t.Data))
+		for key, text := range t.Data {
+			value, err := renderTemplate(t.Name+"/"+key, text, data)
+			if err != nil {
+				return nil, fmt.Errorf("bootstrap ConfigMap %s: %w", t.Name, err)
+			}
+			rendered[key] = value
+		}
+
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      t.Name,
+				Namespace: nsName,
+			},
+			Data: rendered,
+		}
+		cm.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		cm.Labels = objectLabels(tenant, cm.Data)
+		objects = append(objects, cm)
+	}
+
+	for _, obj := range objects {
+		obj.GetLabels()[BootstrapLabelKey] = "true"
+	}
+
+	return objects, nil
+}
+
+// -----------------------------------------------------------------------------
+// reconcileBootstrap applies the bootstrap Secrets and ConfigMaps of the
+// profile, prunes the ones no longer listed and sets the imagePullSecrets of
+// the default ServiceAccount.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) reconcileBootstrap(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	profile *platformv1alpha1.TenantProfile,
+	applied bool,
+) error {
+
+	var (
+		objects     []client.Object
+		pullSecrets []string
+	)
+
+	if profile != nil && profile.Spec.Bootstrap != nil {
+		var err error
+		if objects, err = r.buildBootstrapObjects(ctx, tenant, profile); err != nil {
+			return err
+		}
+		pullSecrets = profile.Spec.Bootstrap.ImagePullSecrets
+	}
+
+	desired := map[string]sets.Set[string]{
+		platformv1alpha1.BootstrapKindSecret:    sets.New[string](),
+		platformv1alpha1.BootstrapKindConfigMap: sets.New[string](),
+	}
+
+	for _, obj := range objects {
+
+		if err := controllerutil.SetControllerReference(tenant, obj, r.Scheme); err != nil {
+			return err
+		}
+
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+
+		res, err := applyObject(ctx, r.Client, obj)
+		if err != nil {
+			return err
+		}
+		if res.drifted(obj.GetLabels()[SpecHashLabelKey], applied) {
+			recordDrift(r.Recorder, tenant, kind, obj.GetNamespace(), obj.GetName())
+		}
+
+		desired[kind].Insert(obj.GetName())
+	}
+
+	// -------------------------------------------------------------------------
+	// Prune bootstrap objects no longer listed in the profile
+	// -------------------------------------------------------------------------
+	if !r.Features.Enabled(features.Pruning) {
+		return r.reconcileImagePullSecrets(ctx, tenant, pullSecrets)
+	}
+
+	selector := []client.ListOption{
+		client.InNamespace(tenant.Spec.Namespace),
+		client.MatchingLabels{
+			ManagedByLabelKey: ManagedByLabelValue,
+			TenantLabelKey:    tenant.Name,
+			BootstrapLabelKey: "true",
+		},
+	}
+
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, selector...); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		if desired[platformv1alpha1.BootstrapKindSecret].Has(secrets.Items[i].Name) {
+			continue
+		}
+		if err := r.Delete(ctx, &secrets.Items[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		audit.Record(ctx, audit.Delete, AuditReasonPruned, &secrets.Items[i], nil)
+	}
+
+	var configMaps corev1.ConfigMapList
+	if err := r.List(ctx, &configMaps, selector...); err != nil {
+		return err
+	}
+	for i := range configMaps.Items {
+		if desired[platformv1alpha1.BootstrapKindConfigMap].Has(configMaps.Items[i].Name) {
+			continue
+		}
+		if err := r.Delete(ctx, &configMaps.Items[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		audit.Record(ctx, audit.Delete, AuditReasonPruned, &configMaps.Items[i], nil)
+	}
+
+	return r.reconcileImagePullSecrets(ctx, tenant, pullSecrets)
+}
+
+// -----------------------------------------------------------------------------
+// reconcileImagePullSecrets sets the profile imagePullSecrets on the default
+// ServiceAccount. The list is atomic for server-side apply, so it is merged by
+// hand: entries added by others are kept, and the ones the operator added are
+// tracked in an annotation so they can be removed again.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) reconcileImagePullSecrets(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	names []string,
+) error {
+
+	names = slices.Clone(names)
+	sort.Strings(names)
+	names = slices.Compact(names)
+
+	// ServiceAccounts are not cached
+	sa := &corev1.ServiceAccount{}
+	err := r.reader().Get(ctx, client.ObjectKey{Namespace: tenant.Spec.Namespace, Name: defaultServiceAccount}, sa)
+	if apierrors.IsNotFound(err) {
+		if len(names) == 0 {
+			return nil
+		}
+
+		// Created ahead of the ServiceAccount controller
+		sa = &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      defaultServiceAccount,
+				Namespace: tenant.Spec.Namespace,
+			},
+		}
+		setImagePullSecrets(sa, names)
+		if err := r.Create(ctx, sa); err != nil {
+			return client.IgnoreAlreadyExists(err)
+		}
+		audit.Record(ctx, audit.Create, AuditReasonCreated, nil, sa)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	original := sa.DeepCopy()
+	setImagePullSecrets(sa, names)
+
+	if equality.Semantic.DeepEqual(original, sa) {
+		return nil
+	}
+
+	if err := r.Patch(ctx, sa, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.Update, AuditReasonUpdated, original, sa)
+	return nil
+}
+
+// setImagePullSecrets replaces the entries previously added by the operator
+// with names, keeping any other entry.
+func setImagePullSecrets(sa *corev1.ServiceAccount, names []string) {
+
+	var previous []string
+	if v := sa.Annotations[ImagePullSecretsAnnotationKey]; v != "" {
+		previous = strings.Split(v, ",")
+	}
+
+	var refs []corev1.LocalObjectReference
+	for _, ref := range sa.ImagePullSecrets {
+		if slices.Contains(previous, ref.Name) || slices.Contains(names, ref.Name) {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	for _, name := range names {
+		refs = append(refs, corev1.LocalObjectReference{Name: name})
+	}
+	sa.ImagePullSecrets = refs
+
+	if len(names) == 0 {
+		delete(sa.Annotations, ImagePullSecretsAnnotationKey)
+		return
+	}
+	if sa.Annotations == nil {
+		sa.Annotations = map[string]string{}
+	}
+	sa.Annotations[ImagePullSecretsAnnotationKey] = strings.Join(names, ",")
+}
+
+// -----------------------------------------------------------------------------
+// tenantsForBootstrapSource maps a Secret or ConfigMap to the Tenants whose
+// profile copies it, so copies follow changes to their source. Only sources
+// in the configured bootstrap.sourceNamespaces are cached and watched.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) tenantsForBootstrapSource(kind string) func(context.Context, client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+
+		var profiles platformv1alpha1.TenantProfileList
+		if err := r.List(
+			ctx,
+			&profiles,
+			client.MatchingFields{
+				profileBootstrapSourceIndex: bootstrapSourceKey(kind, obj.GetNamespace(), obj.GetName()),
+			},
+		); err != nil {
+			log.FromContext(ctx).Error(err, "unable to list TenantProfiles for bootstrap source",
+				"kind", kind, "source", client.ObjectKeyFromObject(obj))
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for i := range profiles.Items {
+			requests = append(requests, r.tenantsForProfile(ctx, &profiles.Items[i])...)
+		}
+
+		return requests
+	}
+}
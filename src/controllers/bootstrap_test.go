@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBootstrapSecretHash(t *testing.T) {
+	g := NewWithT(t)
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-pull", Namespace: "platform-system", UID: "source-uid"},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec:       platformv1alpha1.TenantSpec{Namespace: "team-a"},
+	}
+	profile := &platformv1alpha1.TenantProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: platformv1alpha1.TenantProfileSpec{
+			Bootstrap: &platformv1alpha1.BootstrapSpec{
+				Copy: []platformv1alpha1.BootstrapCopy{{
+					Kind:            platformv1alpha1.BootstrapKindSecret,
+					Name:            "registry-pull",
+					SourceNamespace: "platform-system",
+				}},
+			},
+		},
+	}
+
+	r := &TenantReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(source).Build()}
+
+	build := func() string {
+		objects, err := r.buildBootstrapObjects(context.Background(), tenant, profile)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		return objects[0].GetLabels()[SpecHashLabelKey]
+	}
+
+	hash := build()
+	g.Expect(hash).NotTo(BeEmpty())
+	g.Expect(hash).NotTo(Equal(specHash(struct {
+		Type corev1.SecretType `json:"type"`
+		Data map[string][]byte `json:"data"`
+	}{source.Type, source.Data})))
+
+	// A new version of the source is a new spec, not drift
+	source.Data["password"] = []byte("correct-horse")
+	g.Expect(r.Update(context.Background(), source)).To(Succeed())
+	g.Expect(build()).NotTo(Equal(hash))
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// -----------------------------------------------------------------------------
+// CacheOptions restricts the manager cache to the objects labelled
+// managed-by: namespace-operator for the namespaces and every kind the
+// operator creates in them, and drops managed fields, so large clusters do
+// not pay for objects the operator never manages. Secrets and ConfigMaps of
+// sourceNamespaces are cached whatever their labels, as bootstrap sources.
+// Reads of other objects of those kinds find nothing: they go through the
+// API reader. A Tenant adopting an existing namespace labels it on its first
+// apply.
+// -----------------------------------------------------------------------------
+func CacheOptions(opts cache.Options, sourceNamespaces []string) cache.Options {
+
+	managed := labels.SelectorFromSet(labels.Set{ManagedByLabelKey: ManagedByLabelValue})
+
+	if opts.ByObject == nil {
+		opts.ByObject = map[client.Object]cache.ByObject{}
+	}
+	for _, obj := range []client.Object{
+		&corev1.Namespace{},
+		&corev1.ResourceQuota{},
+		&corev1.LimitRange{},
+		&corev1.ServiceAccount{},
+		&networkingv1.NetworkPolicy{},
+		&rbacv1.RoleBinding{},
+		&appsv1.Deployment{},
+		&appsv1.StatefulSet{},
+		&batchv1.CronJob{},
+	} {
+		opts.ByObject[obj] = cache.ByObject{Label: managed}
+	}
+
+	namespaces := map[string]cache.Config{
+		cache.AllNamespaces: {LabelSelector: managed},
+	}
+	for _, ns := range sourceNamespaces {
+		namespaces[ns] = cache.Config{LabelSelector: labels.Everything()}
+	}
+	opts.ByObject[&corev1.Secret{}] = cache.ByObject{Namespaces: namespaces}
+	opts.ByObject[&corev1.ConfigMap{}] = cache.ByObject{Namespaces: namespaces}
+
+	opts.DefaultTransform = cache.TransformStripManagedFields()
+
+	return opts
+}
+
+// inNamespaces keeps the events of objects in one of namespaces
+func inNamespaces(namespaces []string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return slices.Contains(namespaces, obj.GetNamespace())
+	})
+}
+
+// -----------------------------------------------------------------------------
+// namespaceChangedPredicate drops namespace updates that change neither the
+// labels, the annotations nor the deletion timestamp (status, managed fields,
+// other tools' fields), which never change what the operator applies.
+// -----------------------------------------------------------------------------
+var namespaceChangedPredicate = predicate.Or(
+	predicate.LabelChangedPredicate{},
+	predicate.AnnotationChangedPredicate{},
+	predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetDeletionTimestamp().IsZero() !=
+				e.ObjectNew.GetDeletionTimestamp().IsZero()
+		},
+	},
+)
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestCacheOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	period := time.Hour
+	opts := CacheOptions(cache.Options{SyncPeriod: &period}, []string{"shared"})
+
+	g.Expect(opts.SyncPeriod).To(Equal(&period))
+	g.Expect(opts.DefaultTransform).NotTo(BeNil())
+
+	managed := labels.Set{ManagedByLabelKey: ManagedByLabelValue}
+	unmanaged := labels.Set{ManagedByLabelKey: "someone-else"}
+
+	expectManaged := func(selector labels.Selector) {
+		g.Expect(selector.Matches(managed)).To(BeTrue())
+		g.Expect(selector.Matches(unmanaged)).To(BeFalse())
+		g.Expect(selector.Matches(labels.Set{})).To(BeFalse())
+	}
+
+	kinds := map[string]bool{}
+	for obj, byObject := range opts.ByObject {
+		kind := reflect.TypeOf(obj).Elem().Name()
+		kinds[kind] = true
+
+		switch obj.(type) {
+		case *corev1.Secret, *corev1.ConfigMap:
+			// Bootstrap sources are cached whatever their labels
+			g.Expect(byObject.Label).To(BeNil(), kind)
+			g.Expect(byObject.Namespaces).To(HaveLen(2), kind)
+			g.Expect(byObject.Namespaces["shared"].LabelSelector.Matches(labels.Set{})).To(BeTrue(), kind)
+			expectManaged(byObject.Namespaces[cache.AllNamespaces].LabelSelector)
+		default:
+			expectManaged(byObject.Label)
+		}
+	}
+	g.Expect(kinds).To(Equal(map[string]bool{
+		"Namespace":      true,
+		"ResourceQuota":  true,
+		"LimitRange":     true,
+		"ServiceAccount": true,
+		"NetworkPolicy":  true,
+		"RoleBinding":    true,
+		"Deployment":     true,
+		"StatefulSet":    true,
+		"CronJob":        true,
+		"Secret":         true,
+		"ConfigMap":      true,
+	}))
+
+	g.Expect(inNamespaces([]string{"shared"}).Generic(event.GenericEvent{
+		Object: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "shared"}},
+	})).To(BeTrue())
+	g.Expect(inNamespaces([]string{"shared"}).Generic(event.GenericEvent{
+		Object: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}},
+	})).To(BeFalse())
+}
+
+func TestNamespaceChangedPredicate(t *testing.T) {
+	g := NewWithT(t)
+
+	old := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "team-a",
+			Labels: map[string]string{ManagedByLabelKey: ManagedByLabelValue},
+		},
+	}
+
+	update := func(mutate func(*corev1.Namespace)) bool {
+		ns := old.DeepCopy()
+		mutate(ns)
+		return namespaceChangedPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: ns})
+	}
+
+	g.Expect(update(func(ns *corev1.Namespace) {
+		ns.Status.Phase = corev1.NamespaceTerminating
+		ns.ResourceVersion = "2"
+	})).To(BeFalse())
+
+	g.Expect(update(func(ns *corev1.Namespace) {
+		ns.Labels["team"] = "a"
+	})).To(BeTrue())
+
+	g.Expect(update(func(ns *corev1.Namespace) {
+		ns.Annotations = map[string]string{"owner": "a"}
+	})).To(BeTrue())
+
+	g.Expect(update(func(ns *corev1.Namespace) {
+		now := metav1.Now()
+		ns.DeletionTimestamp = &now
+	})).To(BeTrue())
+
+	g.Expect(namespaceChangedPredicate.Create(event.CreateEvent{Object: old})).To(BeTrue())
+	g.Expect(namespaceChangedPredicate.Delete(event.DeleteEvent{Object: old})).To(BeTrue())
+}
@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/audit"
+	"github.com/tngs/namespace-operator/features"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// -----------------------------------------------------------------------------
+// classRestriction splits the classes existing in the cluster into the ones a
+// tenant may use and the ones blocked by a zero quota. A nil allowlist allows
+// every class.
+// -----------------------------------------------------------------------------
+type classRestriction struct {
+	Allowed []string
+	Denied  []string
+}
+
+func restrictClasses(existing, allowlist []string) classRestriction {
+	var res classRestriction
+
+	sorted := slices.Clone(existing)
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		if len(allowlist) == 0 || slices.Contains(allowlist, name) {
+			res.Allowed = append(res.Allowed, name)
+		} else {
+			res.Denied = append(res.Denied, name)
+		}
+	}
+
+	return res
+}
+
+// -----------------------------------------------------------------------------
+// classRestrictions resolves the StorageClass and PriorityClass allowlists of
+// the profile against the classes present in the cluster.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) classRestrictions(
+	ctx context.Context,
+	profile *platformv1alpha1.TenantProfile,
+) (storage, priority classRestriction, err error) {
+
+	var storageAllow, priorityAllow []string
+	if profile != nil {
+		storageAllow = profile.Spec.AllowedStorageClasses
+		priorityAllow = profile.Spec.AllowedPriorityClasses
+	}
+
+	var storageClasses storagev1.StorageClassList
+	if err := r.List(ctx, &storageClasses); err != nil {
+		return storage, priority, err
+	}
+	names := make([]string, 0, len(storageClasses.Items))
+	for _, sc := range storageClasses.Items {
+		names = append(names, sc.Name)
+	}
+	storage = restrictClasses(names, storageAllow)
+
+	var priorityClasses schedulingv1.PriorityClassList
+	if err := r.List(ctx, &priorityClasses); err != nil {
+		return storage, priority, err
+	}
+	names = make([]string, 0, len(priorityClasses.Items))
+	for _, pc := range priorityClasses.Items {
+		names = append(names, pc.Name)
+	}
+	priority = restrictClasses(names, priorityAllow)
+
+	return storage, priority, nil
+}
+
+// -----------------------------------------------------------------------------
+// storageClassQuota returns the zero quota entries blocking the denied
+// StorageClasses, to merge into the tenant ResourceQuota.
+// -----------------------------------------------------------------------------
+func storageClassQuota(denied []string) corev1.ResourceList {
+	hard := corev1.ResourceList{}
+	for _, class := range denied {
+		prefix := class + ".storageclass.storage.k8s.io/"
+		hard[corev1.ResourceName(prefix+"requests.storage")] = resource.MustParse("0")
+		hard[corev1.ResourceName(prefix+"persistentvolumeclaims")] = resource.MustParse("0")
+	}
+	return hard
+}
+
+// -----------------------------------------------------------------------------
+// reconcilePriorityClassQuota applies a quota allowing zero pods in the denied
+// PriorityClasses, or deletes the managed one when no class is denied.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) reconcilePriorityClassQuota(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	denied []string,
+	applied bool,
+) error {
+
+	rq := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PriorityClassQuotaName,
+			Namespace: tenant.Spec.Namespace,
+		},
+	}
+
+	if len(denied) == 0 {
+		if !r.Features.Enabled(features.Pruning) {
+			return nil
+		}
+
+		// A quota of the same name the operator did not create is left alone
+		if err := r.Get(ctx, client.ObjectKeyFromObject(rq), rq); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if rq.Labels[ManagedByLabelKey] != ManagedByLabelValue {
+			return nil
+		}
+
+		if err := r.Delete(ctx, rq); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		audit.Record(ctx, audit.Delete, AuditReasonPruned, rq, nil)
+		return nil
+	}
+
+	rq.Spec = corev1.ResourceQuotaSpec{
+		Hard: corev1.ResourceList{
+			corev1.ResourcePods: resource.MustParse("0"),
+		},
+		ScopeSelector: &corev1.ScopeSelector{
+			MatchExpressions: []corev1.ScopedResourceSelectorRequirement{
+				{
+					ScopeName: corev1.ResourceQuotaScopePriorityClass,
+					Operator:  corev1.ScopeSelectorOpIn,
+					Values:    denied,
+				},
+			},
+		},
+	}
+	rq.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ResourceQuota"))
+	rq.Labels = objectLabels(tenant, rq.Spec)
+
+	if err := controllerutil.SetControllerReference(tenant, rq, r.Scheme); err != nil {
+		return err
+	}
+
+	res, err := applyObject(ctx, r.Client, rq)
+	if err != nil {
+		return err
+	}
+	if res.drifted(rq.Labels[SpecHashLabelKey], applied) {
+		recordDrift(r.Recorder, tenant, "ResourceQuota", rq.Namespace, rq.Name)
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// tenantsForClassChange requeues the Tenants whose profile restricts classes
+// when a StorageClass or PriorityClass is added or removed, so new classes
+// are blocked as soon as they appear.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) tenantsForClassChange(
+	ctx context.Context,
+	_ client.Object,
+) []reconcile.Request {
+
+	var profiles platformv1alpha1.TenantProfileList
+	if err := r.List(ctx, &profiles); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list TenantProfiles")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range profiles.Items {
+		profile := &profiles.Items[i]
+		if len(profile.Spec.AllowedStorageClasses) == 0 &&
+			len(profile.Spec.AllowedPriorityClasses) == 0 {
+			continue
+		}
+		requests = append(requests, r.tenantsForProfile(ctx, profile)...)
+	}
+
+	return requests
+}
+
+// classLifecyclePredicate only lets through class creations and deletions.
+var classLifecyclePredicate = predicate.Funcs{
+	UpdateFunc: func(event.UpdateEvent) bool { return false },
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRestrictClasses(t *testing.T) {
+	g := NewWithT(t)
+
+	existing := []string{"standard", "fast-ssd", "legacy"}
+
+	// No allowlist: everything allowed
+	res := restrictClasses(existing, nil)
+	g.Expect(res.Allowed).To(Equal([]string{"fast-ssd", "legacy", "standard"}))
+	g.Expect(res.Denied).To(BeEmpty())
+
+	// Allowlist: other classes denied, unknown allowed names ignored
+	res = restrictClasses(existing, []string{"standard", "missing"})
+	g.Expect(res.Allowed).To(Equal([]string{"standard"}))
+	g.Expect(res.Denied).To(Equal([]string{"fast-ssd", "legacy"}))
+
+	hard := storageClassQuota(res.Denied)
+	g.Expect(hard).To(HaveLen(4))
+	q := hard[corev1.ResourceName("legacy.storageclass.storage.k8s.io/requests.storage")]
+	g.Expect(q.IsZero()).To(BeTrue())
+	g.Expect(hard).To(HaveKey(corev1.ResourceName("fast-ssd.storageclass.storage.k8s.io/persistentvolumeclaims")))
+}
+
+func TestPriorityClassQuotaPruning(t *testing.T) {
+	ctx := context.Background()
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec:       platformv1alpha1.TenantSpec{Namespace: "team-a"},
+	}
+
+	quota := func(labels map[string]string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      PriorityClassQuotaName,
+				Namespace: "team-a",
+				Labels:    labels,
+			},
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		labels map[string]string
+		kept   bool
+	}{
+		"managed":   {labels: map[string]string{ManagedByLabelKey: ManagedByLabelValue}},
+		"unmanaged": {labels: map[string]string{"team": "a"}, kept: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			rq := quota(tc.labels)
+
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(rq).Build()
+			r := &TenantReconciler{Client: c, Scheme: scheme}
+
+			g.Expect(r.reconcilePriorityClassQuota(ctx, tenant, nil, false)).To(Succeed())
+
+			err := c.Get(ctx, client.ObjectKeyFromObject(rq), &corev1.ResourceQuota{})
+			if tc.kept {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package controllers
 
 import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+// setCondition adds or updates a condition. LastTransitionTime only moves
+// when the status changes, so it tells since when a condition holds.
 func setCondition(
 	conditions *[]metav1.Condition,
 	condType string,
@@ -12,12 +14,16 @@ func setCondition(
 
 	for i, c := range *conditions {
 		if c.Type == condType {
+			transition := c.LastTransitionTime
+			if c.Status != status {
+				transition = now
+			}
 			(*conditions)[i] = metav1.Condition{
 				Type:               condType,
 				Status:             status,
 				Reason:             reason,
 				Message:            message,
-				LastTransitionTime: now,
+				LastTransitionTime: transition,
 			}
 			return
 		}
@@ -4,3 +4,110 @@ const (
 	ManagedByLabelKey   = "managed-by"
 	ManagedByLabelValue = "namespace-operator"
 )
+
+const (
+	ResourceQuotaName = "tenant-quota"
+	LimitRangeName    = "tenant-limits"
+
+	// PriorityClassQuotaName blocks the PriorityClasses a tenant may not use
+	PriorityClassQuotaName = "tenant-priority-classes"
+)
+
+// EventReasonDriftCorrected is recorded when a managed object is restored
+// after being edited or deleted outside of the operator.
+const EventReasonDriftCorrected = "DriftCorrected"
+
+// EventReasonReservedMetadata is recorded when namespace metadata uses
+// reserved keys, which are then ignored.
+const EventReasonReservedMetadata = "ReservedMetadataIgnored"
+
+// EventReasonPodSecurityClamped is recorded when a tenant asks for a Pod
+// Security enforce level below its profile minimum.
+const EventReasonPodSecurityClamped = "PodSecurityLevelRaised"
+
+// ReservedKeyPrefix marks labels and annotations owned by the operator;
+// tenants and profiles cannot set them on the namespace.
+const ReservedKeyPrefix = "platform.example.com/"
+
+// Labels set on every object generated for a Tenant
+const (
+	TenantLabelKey   = "platform.example.com/tenant"
+	ProfileLabelKey  = "platform.example.com/profile"
+	SpecHashLabelKey = "platform.example.com/spec-hash"
+)
+
+// FieldOwner is the field manager used for every server-side apply
+const FieldOwner = "namespace-operator"
+
+// Bootstrap objects copied or rendered into tenant namespaces
+const (
+	// BootstrapLabelKey marks bootstrap Secrets and ConfigMaps, for pruning
+	BootstrapLabelKey = "platform.example.com/bootstrap"
+
+	// SourceAnnotationKey records the namespace/name a copy was made from
+	SourceAnnotationKey = "platform.example.com/source"
+
+	// ImagePullSecretsAnnotationKey lists the imagePullSecrets the operator
+	// added to the default ServiceAccount, so only those are removed later
+	ImagePullSecretsAnnotationKey = "platform.example.com/image-pull-secrets"
+)
+
+// Tenant suspension
+const (
+	// SuspendedReplicasAnnotationKey keeps the replica count of a Deployment or
+	// StatefulSet scaled to zero while its tenant is suspended
+	SuspendedReplicasAnnotationKey = "platform.example.com/suspended-replicas"
+
+	// SuspendedCronJobAnnotationKey keeps the suspend flag a CronJob had
+	// before its tenant was suspended
+	SuspendedCronJobAnnotationKey = "platform.example.com/suspended-cronjob"
+
+	EventReasonSuspended = "Suspended"
+	EventReasonResumed   = "Resumed"
+
+	// EventReasonInvalidSchedule is recorded when the hibernation schedule
+	// cannot be evaluated; the tenant then stays awake
+	EventReasonInvalidSchedule = "InvalidSchedule"
+)
+
+// Reasons recorded in the audit log, next to the event reasons above
+const (
+	AuditReasonCreated = "Created"
+	AuditReasonUpdated = "Updated"
+
+	// AuditReasonPruned is recorded when an object is no longer desired
+	AuditReasonPruned = "Pruned"
+
+	// AuditReasonTenantDeleted is recorded when the namespace of a deleted
+	// Tenant is removed
+	AuditReasonTenantDeleted = "TenantDeleted"
+)
+
+// Tenant expiration
+const (
+	// EventReasonExpiringSoon is recorded when a tenant enters the warning
+	// window before its expiration
+	EventReasonExpiringSoon = "ExpiringSoon"
+
+	// EventReasonExpired is recorded when an expired tenant is deleted
+	EventReasonExpired = "Expired"
+
+	// EventReasonInvalidExpiry is recorded when the extend-until annotation
+	// cannot be parsed; it is then ignored
+	EventReasonInvalidExpiry = "InvalidExpiry"
+)
+
+// EventReasonRoleNotAllowed is recorded when an access binding uses a
+// ClusterRole outside access.allowedRoles, which is then not bound.
+const EventReasonRoleNotAllowed = "RoleNotAllowed"
+
+// Tenant deletion
+const (
+	// EventReasonReservedNamespace is recorded when a tenant targets a
+	// namespace reserved by the operator configuration
+	EventReasonReservedNamespace = "ReservedNamespace"
+
+	// AuditReasonTenantReleased is recorded when the namespace of a deleted
+	// Tenant is kept under the Retain deletion policy
+	AuditReasonTenantReleased = "TenantReleased"
+)
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"context"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/audit"
+	"github.com/tngs/namespace-operator/config"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// deletionPolicy returns spec.deletionPolicy, or the configured default
+func deletionPolicy(tenant *platformv1alpha1.Tenant, cfg *config.OperatorConfig) string {
+	if tenant.Spec.DeletionPolicy != "" {
+		return tenant.Spec.DeletionPolicy
+	}
+	return cfg.DefaultDeletionPolicy
+}
+
+// -----------------------------------------------------------------------------
+// cleanupNamespace deletes the namespace of a deleted Tenant, or releases it
+// under the Retain policy. Reserved namespaces, and namespaces the Tenant does
+// not control (adopted, or taken over by another owner), are never deleted.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) cleanupNamespace(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	cfg *config.OperatorConfig,
+) error {
+
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: tenant.Spec.Namespace}, ns); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if cfg.IsReserved(ns.Name) ||
+		!metav1.IsControlledBy(ns, tenant) ||
+		deletionPolicy(tenant, cfg) == platformv1alpha1.DeletionPolicyRetain {
+		return r.releaseNamespace(ctx, tenant, ns)
+	}
+
+	if err := r.Delete(ctx, ns); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	audit.Record(ctx, audit.Delete, AuditReasonTenantDeleted, ns, nil)
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// releaseNamespace removes the tenant owner reference and labels from the
+// namespace, so neither the garbage collector nor the operator touch it once
+// the Tenant is gone. Its content is kept, except the objects owned by the
+// Tenant (quota, limits, bindings, bootstrap copies).
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) releaseNamespace(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	ns *corev1.Namespace,
+) error {
+
+	before := ns.DeepCopy()
+
+	var refs []metav1.OwnerReference
+	for _, ref := range ns.OwnerReferences {
+		if ref.UID != tenant.UID {
+			refs = append(refs, ref)
+		}
+	}
+	ns.OwnerReferences = refs
+	for k := range tenantLabels(tenant) {
+		delete(ns.Labels, k)
+	}
+
+	if err := r.Patch(ctx, ns, client.MergeFrom(before)); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	audit.Record(ctx, audit.Update, AuditReasonTenantReleased, before, ns)
+
+	return nil
+}
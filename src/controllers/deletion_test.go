@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/config"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTenantDeletionPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	deleted := func(policy, namespace string) (*platformv1alpha1.Tenant, *corev1.Namespace) {
+		now := metav1.Now()
+		tenant := &platformv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "team-a",
+				UID:               "tenant-uid",
+				Finalizers:        []string{tenantFinalizer},
+				DeletionTimestamp: &now,
+			},
+			Spec: platformv1alpha1.TenantSpec{
+				Namespace:      namespace,
+				DeletionPolicy: policy,
+			},
+		}
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   namespace,
+				Labels: map[string]string{"team": "a"},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: platformv1alpha1.GroupVersion.String(),
+					Kind:       "Tenant",
+					Name:       tenant.Name,
+					UID:        tenant.UID,
+					Controller: ptr.To(true),
+				}},
+			},
+		}
+		for k, v := range tenantLabels(tenant) {
+			ns.Labels[k] = v
+		}
+		return tenant, ns
+	}
+
+	reconcileDeleted := func(g *WithT, cfg *config.OperatorConfig, tenant *platformv1alpha1.Tenant, ns *corev1.Namespace) client.Client {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tenant, ns).Build()
+		r := &TenantReconciler{
+			Client:   c,
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+			Config:   config.NewStore(cfg),
+		}
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tenant)})
+		g.Expect(err).NotTo(HaveOccurred())
+		return c
+	}
+
+	t.Run("delete", func(t *testing.T) {
+		g := NewWithT(t)
+		tenant, ns := deleted("", "team-a")
+
+		c := reconcileDeleted(g, config.Default(), tenant, ns)
+
+		err := c.Get(ctx, client.ObjectKeyFromObject(ns), &corev1.Namespace{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	t.Run("retain by default", func(t *testing.T) {
+		g := NewWithT(t)
+		tenant, ns := deleted("", "team-a")
+
+		cfg := config.Default()
+		cfg.DefaultDeletionPolicy = platformv1alpha1.DeletionPolicyRetain
+		c := reconcileDeleted(g, cfg, tenant, ns)
+
+		kept := &corev1.Namespace{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(ns), kept)).To(Succeed())
+		g.Expect(kept.OwnerReferences).To(BeEmpty())
+		g.Expect(kept.Labels).To(Equal(map[string]string{"team": "a"}))
+	})
+
+	t.Run("tenant policy wins", func(t *testing.T) {
+		g := NewWithT(t)
+		tenant, ns := deleted(platformv1alpha1.DeletionPolicyDelete, "team-a")
+
+		cfg := config.Default()
+		cfg.DefaultDeletionPolicy = platformv1alpha1.DeletionPolicyRetain
+		c := reconcileDeleted(g, cfg, tenant, ns)
+
+		err := c.Get(ctx, client.ObjectKeyFromObject(ns), &corev1.Namespace{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	t.Run("namespace not controlled by the tenant is released", func(t *testing.T) {
+		g := NewWithT(t)
+		tenant, ns := deleted(platformv1alpha1.DeletionPolicyDelete, "team-a")
+		ns.OwnerReferences[0].Controller = nil
+
+		c := reconcileDeleted(g, config.Default(), tenant, ns)
+
+		kept := &corev1.Namespace{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(ns), kept)).To(Succeed())
+		g.Expect(kept.OwnerReferences).To(BeEmpty())
+	})
+
+	t.Run("reserved namespace is never deleted", func(t *testing.T) {
+		g := NewWithT(t)
+		tenant, ns := deleted(platformv1alpha1.DeletionPolicyDelete, "kube-public")
+
+		c := reconcileDeleted(g, config.Default(), tenant, ns)
+
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(ns), &corev1.Namespace{})).To(Succeed())
+	})
+}
+
+func TestTenantReservedNamespace(t *testing.T) {
+	g := NewWithT(t)
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "system", Finalizers: []string{tenantFinalizer}},
+		Spec:       platformv1alpha1.TenantSpec{Namespace: "kube-system"},
+	}
+
+	t.Cleanup(func() { forgetTenantMetrics(tenant.Name) })
+
+	recorder := record.NewFakeRecorder(10)
+	r := &TenantReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(tenant).Build(),
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tenant)})
+	g.Expect(reconcileOutcome(ctrl.Result{}, err)).To(Equal(OutcomeInvalidConfig))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring(EventReasonReservedNamespace)))
+}
+
+func TestNamespaceAdopted(t *testing.T) {
+	ctx := context.Background()
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", UID: "tenant-uid"},
+		Spec:       platformv1alpha1.TenantSpec{Namespace: "team-a"},
+	}
+
+	for name, tc := range map[string]struct {
+		ns      *corev1.Namespace
+		adopted bool
+	}{
+		"missing": {},
+		"created by the tenant": {ns: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "team-a",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: platformv1alpha1.GroupVersion.String(),
+				Kind:       "Tenant",
+				Name:       tenant.Name,
+				UID:        tenant.UID,
+				Controller: ptr.To(true),
+			}},
+		}}},
+		"existing": {ns: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}, adopted: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tc.ns != nil {
+				builder = builder.WithObjects(tc.ns)
+			}
+			r := &TenantReconciler{Client: builder.Build(), Scheme: scheme}
+
+			adopted, err := r.namespaceAdopted(ctx, tenant)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(adopted).To(Equal(tc.adopted))
+		})
+	}
+}
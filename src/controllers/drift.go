@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// -----------------------------------------------------------------------------
+// managedByPredicate only lets through events for objects carrying the
+// managed-by label, so edits to unrelated quotas or policies are ignored.
+// -----------------------------------------------------------------------------
+var managedByPredicate = predicate.NewPredicateFuncs(func(obj client.Object) bool {
+	return obj.GetLabels()[ManagedByLabelKey] == ManagedByLabelValue
+})
+
+// -----------------------------------------------------------------------------
+// tenantApplied reports whether the current tenant spec and profile have
+// already been applied once, meaning any difference between the live and
+// desired state of a child object was introduced outside of the operator.
+// -----------------------------------------------------------------------------
+func tenantApplied(
+	tenant *platformv1alpha1.Tenant,
+	profile *platformv1alpha1.TenantProfile,
+) bool {
+	if tenant.Status.ObservedGeneration != tenant.Generation {
+		return false
+	}
+	return profile == nil || tenant.Status.ObservedProfileGeneration == profile.Generation
+}
+
+// -----------------------------------------------------------------------------
+// drifted reports whether an apply corrected drift: the object was recreated
+// although the tenant was already applied, or it was changed although its
+// spec-hash label already matched the desired spec.
+// -----------------------------------------------------------------------------
+func (a applyResult) drifted(hash string, applied bool) bool {
+	if !a.existed {
+		return applied
+	}
+	return a.changed && a.prevHash == hash
+}
+
+// -----------------------------------------------------------------------------
+// recordDrift emits a DriftCorrected event on the owning object and counts the
+// correction per object kind.
+// -----------------------------------------------------------------------------
+func recordDrift(
+	recorder record.EventRecorder,
+	owner client.Object,
+	kind, namespace, name string,
+) {
+	DriftCorrected.WithLabelValues(kind).Inc()
+
+	events(recorder).Eventf(
+		owner,
+		corev1.EventTypeNormal,
+		EventReasonDriftCorrected,
+		"Restored %s %s/%s to the desired state",
+		kind, namespace, name,
+	)
+}
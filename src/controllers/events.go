@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// -----------------------------------------------------------------------------
+// events returns recorder, or one dropping every event when it is nil: every
+// event of the reconcilers goes through it, so a reconciler built without a
+// Recorder never panics on an event.
+// -----------------------------------------------------------------------------
+func events(recorder record.EventRecorder) record.EventRecorder {
+	if recorder == nil {
+		return discardRecorder{}
+	}
+	return recorder
+}
+
+// discardRecorder drops every event
+type discardRecorder struct{}
+
+func (discardRecorder) Event(runtime.Object, string, string, string) {}
+
+func (discardRecorder) Eventf(runtime.Object, string, string, string, ...any) {}
+
+func (discardRecorder) AnnotatedEventf(runtime.Object, map[string]string, string, string, string, ...any) {
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// -----------------------------------------------------------------------------
+// tenantExpiry returns the time at which the tenant expires: spec.expiresAt,
+// or the creation time plus spec.ttl, postponed by the extend-until
+// annotation. It returns nil when the tenant does not expire.
+// -----------------------------------------------------------------------------
+func tenantExpiry(tenant *platformv1alpha1.Tenant) (*time.Time, error) {
+
+	var expiresAt time.Time
+
+	switch {
+	case tenant.Spec.ExpiresAt != nil:
+		expiresAt = tenant.Spec.ExpiresAt.Time
+	case tenant.Spec.TTL != nil:
+		expiresAt = tenant.CreationTimestamp.Add(tenant.Spec.TTL.Duration)
+	default:
+		return nil, nil
+	}
+
+	if v := tenant.Annotations[platformv1alpha1.ExtendUntilAnnotation]; v != "" {
+		until, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return &expiresAt, fmt.Errorf("invalid %s annotation %q: %w",
+				platformv1alpha1.ExtendUntilAnnotation, v, err)
+		}
+		if until.After(expiresAt) {
+			expiresAt = until
+		}
+	}
+
+	return &expiresAt, nil
+}
+
+// -----------------------------------------------------------------------------
+// expiryRequeue returns when to reconcile the tenant again: at the start of
+// the warning window, then at expiry.
+// -----------------------------------------------------------------------------
+func expiryRequeue(expiresAt, now time.Time, warning time.Duration) time.Duration {
+	if warnAt := expiresAt.Add(-warning); now.Before(warnAt) {
+		return warnAt.Sub(now)
+	}
+	return expiresAt.Sub(now)
+}
+
+// requeueBefore shortens the requeue delay of result to after, if sooner
+func requeueBefore(result *ctrl.Result, after time.Duration) {
+	if result.RequeueAfter == 0 || after < result.RequeueAfter {
+		result.RequeueAfter = after
+	}
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/features"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	. "github.com/onsi/gomega"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTenantExpiry(t *testing.T) {
+	g := NewWithT(t)
+
+	created := time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "preview-42",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: platformv1alpha1.TenantSpec{Namespace: "preview-42"},
+	}
+
+	// No expiration
+	expiresAt, err := tenantExpiry(tenant)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(expiresAt).To(BeNil())
+
+	// TTL counts from the creation
+	tenant.Spec.TTL = &metav1.Duration{Duration: 72 * time.Hour}
+	expiresAt, err = tenantExpiry(tenant)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*expiresAt).To(BeTemporally("==", created.Add(72*time.Hour)))
+
+	// An earlier extension is ignored
+	tenant.Annotations = map[string]string{platformv1alpha1.ExtendUntilAnnotation: "2026-10-15T10:00:00Z"}
+	expiresAt, err = tenantExpiry(tenant)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*expiresAt).To(BeTemporally("==", created.Add(72*time.Hour)))
+
+	// A later one postpones the expiration
+	tenant.Annotations[platformv1alpha1.ExtendUntilAnnotation] = "2026-10-20T10:00:00Z"
+	expiresAt, err = tenantExpiry(tenant)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*expiresAt).To(BeTemporally("==", time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)))
+
+	// An invalid one is reported, the expiration stands
+	tenant.Annotations[platformv1alpha1.ExtendUntilAnnotation] = "next week"
+	expiresAt, err = tenantExpiry(tenant)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(*expiresAt).To(BeTemporally("==", created.Add(72*time.Hour)))
+
+	// expiresAt wins over the creation time
+	tenant.Annotations = nil
+	tenant.Spec.TTL = nil
+	tenant.Spec.ExpiresAt = &metav1.Time{Time: created.Add(time.Hour)}
+	expiresAt, err = tenantExpiry(tenant)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*expiresAt).To(BeTemporally("==", created.Add(time.Hour)))
+}
+
+func TestExpiryRequeue(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)
+
+	// Before the warning window: come back when it opens
+	g.Expect(expiryRequeue(now.Add(72*time.Hour), now, 24*time.Hour)).To(Equal(48 * time.Hour))
+
+	// Inside it: come back at expiry
+	g.Expect(expiryRequeue(now.Add(2*time.Hour), now, 24*time.Hour)).To(Equal(2 * time.Hour))
+}
+
+func TestExpirationFeatureGate(t *testing.T) {
+	ctx := context.Background()
+
+	reconcileExpired := func(g *WithT, gates *features.Gates) *platformv1alpha1.Tenant {
+		// The reserved namespace stops the reconcile right after expiration
+		tenant := &platformv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "preview-42", Finalizers: []string{tenantFinalizer}},
+			Spec: platformv1alpha1.TenantSpec{
+				Namespace: "kube-public",
+				ExpiresAt: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			},
+		}
+		t.Cleanup(func() { forgetTenantMetrics(tenant.Name) })
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tenant).Build()
+		r := &TenantReconciler{
+			Client:   c,
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+			Features: gates,
+		}
+		_, _ = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tenant)})
+
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(tenant), tenant)).To(Succeed())
+		return tenant
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		g := NewWithT(t)
+		tenant := reconcileExpired(g, nil)
+		g.Expect(tenant.DeletionTimestamp).NotTo(BeNil())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		g := NewWithT(t)
+		gates, err := features.New(map[string]bool{string(features.Expiration): false})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		tenant := reconcileExpired(g, gates)
+		g.Expect(tenant.DeletionTimestamp).To(BeNil())
+	})
+}
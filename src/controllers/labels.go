@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// tenantLabels returns the label set shared by every object generated for a
+// Tenant. A nil tenant (default policies for an unowned namespace) only gets
+// the managed-by label.
+// -----------------------------------------------------------------------------
+func tenantLabels(tenant *platformv1alpha1.Tenant) map[string]string {
+	labels := map[string]string{
+		ManagedByLabelKey: ManagedByLabelValue,
+	}
+
+	if tenant == nil {
+		return labels
+	}
+
+	labels[TenantLabelKey] = tenant.Name
+	if tenant.Spec.Profile != nil {
+		labels[ProfileLabelKey] = *tenant.Spec.Profile
+	}
+
+	return labels
+}
+
+// -----------------------------------------------------------------------------
+// objectLabels returns the tenant label set plus the hash of the desired spec,
+// so an object edited in place can be told apart from a spec change.
+// -----------------------------------------------------------------------------
+func objectLabels(tenant *platformv1alpha1.Tenant, spec any) map[string]string {
+	labels := tenantLabels(tenant)
+	labels[SpecHashLabelKey] = specHash(spec)
+	return labels
+}
+
+// specHash returns a short, label-safe hash of the JSON form of spec.
+func specHash(spec any) string {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// -----------------------------------------------------------------------------
+// namespaceMetadata merges the profile default namespace labels/annotations
+// with the tenant's own (tenant wins), then lays the operator labels on top.
+// Pod Security labels are added by the caller.
+// Reserved keys are dropped and returned so the caller can report them; the
+// CRD rejects them already, this only guards objects admitted before that.
+// -----------------------------------------------------------------------------
+func namespaceMetadata(
+	tenant *platformv1alpha1.Tenant,
+	profile *platformv1alpha1.TenantProfile,
+) (labels, annotations map[string]string, reserved []string) {
+
+	labels = map[string]string{}
+	annotations = map[string]string{}
+
+	var sources []*platformv1alpha1.NamespaceMetadata
+	if profile != nil {
+		sources = append(sources, profile.Spec.NamespaceMetadata)
+	}
+	sources = append(sources, tenant.Spec.NamespaceMetadata)
+
+	for _, src := range sources {
+		if src == nil {
+			continue
+		}
+		for k, v := range src.Labels {
+			if isReservedKey(k) {
+				reserved = append(reserved, k)
+				continue
+			}
+			labels[k] = v
+		}
+		for k, v := range src.Annotations {
+			if isReservedKey(k) {
+				reserved = append(reserved, k)
+				continue
+			}
+			annotations[k] = v
+		}
+	}
+
+	for k, v := range tenantLabels(tenant) {
+		labels[k] = v
+	}
+
+	sort.Strings(reserved)
+	return labels, annotations, reserved
+}
+
+// isReservedKey reports whether a label/annotation key belongs to the operator.
+// Scheduler annotations are reserved so tenants cannot leave their node pool.
+func isReservedKey(key string) bool {
+	return key == ManagedByLabelKey ||
+		strings.HasPrefix(key, ReservedKeyPrefix) ||
+		strings.HasPrefix(key, PodSecurityLabelPrefix) ||
+		strings.HasPrefix(key, platformv1alpha1.SchedulerAnnotationPrefix)
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNamespaceMetadataMerge(t *testing.T) {
+	g := NewWithT(t)
+
+	profileName := "standard"
+
+	profile := &platformv1alpha1.TenantProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: profileName},
+		Spec: platformv1alpha1.TenantProfileSpec{
+			NamespaceMetadata: &platformv1alpha1.NamespaceMetadata{
+				Labels: map[string]string{
+					"cost-center":     "platform",
+					"istio-injection": "enabled",
+				},
+				Annotations: map[string]string{
+					"owner": "platform@example.com",
+				},
+			},
+		},
+	}
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec: platformv1alpha1.TenantSpec{
+			Namespace: "team-a",
+			Profile:   &profileName,
+			NamespaceMetadata: &platformv1alpha1.NamespaceMetadata{
+				Labels: map[string]string{
+					"cost-center":                "team-a",
+					ManagedByLabelKey:            "someone-else",
+					TenantLabelKey:               "team-b",
+					"platform.example.com/extra": "x",
+				},
+			},
+		},
+	}
+
+	labels, annotations, reserved := namespaceMetadata(tenant, profile)
+
+	// Tenant overrides profile defaults
+	g.Expect(labels).To(HaveKeyWithValue("cost-center", "team-a"))
+	g.Expect(labels).To(HaveKeyWithValue("istio-injection", "enabled"))
+	g.Expect(annotations).To(HaveKeyWithValue("owner", "platform@example.com"))
+
+	// Platform labels cannot be spoofed
+	g.Expect(labels).To(HaveKeyWithValue(ManagedByLabelKey, ManagedByLabelValue))
+	g.Expect(labels).To(HaveKeyWithValue(TenantLabelKey, "team-a"))
+	g.Expect(labels).To(HaveKeyWithValue(ProfileLabelKey, profileName))
+	g.Expect(labels).NotTo(HaveKey("platform.example.com/extra"))
+	g.Expect(reserved).To(ConsistOf(
+		ManagedByLabelKey,
+		TenantLabelKey,
+		"platform.example.com/extra",
+	))
+}
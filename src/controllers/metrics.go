@@ -1,8 +1,21 @@
 package controllers
 
 import (
+	"errors"
+
 	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconcile outcomes, see ReconcileTotal
+const (
+	OutcomeSuccess       = "success"
+	OutcomeError         = "error"
+	OutcomeRequeue       = "requeue"
+	OutcomeInvalidConfig = "invalid_config"
 )
 
 var (
@@ -27,6 +40,63 @@ var (
 			Buckets: prometheus.DefBuckets,
 		},
 	)
+
+	DriftCorrected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "namespace_operator_drift_corrected_total",
+			Help: "Total number of managed objects restored after drifting from the desired state",
+		},
+		[]string{"kind"},
+	)
+
+	// Per-tenant series are deleted with the tenant, so their cardinality is
+	// bounded by the number of tenants.
+	TenantQuota = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "namespace_operator_tenant_quota",
+			Help: "Hard limit and usage of the tenant ResourceQuota per resource",
+		},
+		[]string{"tenant", "resource", "type"},
+	)
+
+	TenantReady = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "namespace_operator_tenant_ready",
+			Help: "Whether the tenant configuration is applied (1) or invalid (0)",
+		},
+		[]string{"tenant"},
+	)
+
+	ReconcileTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "namespace_operator_reconcile_total",
+			Help: "Total number of reconciles per controller and outcome",
+		},
+		[]string{"controller", "outcome"},
+	)
+
+	ChildApplies = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "namespace_operator_child_applies_total",
+			Help: "Total number of server-side applies of managed objects per kind",
+		},
+		[]string{"kind"},
+	)
+
+	ProfileTenants = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "namespace_operator_profile_tenants",
+			Help: "Number of Tenants using each TenantProfile",
+		},
+		[]string{"profile"},
+	)
+
+	TenantsExpired = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "namespace_operator_tenants_expired_total",
+			Help: "Total number of Tenants deleted after expiring",
+		},
+	)
 )
 
 func init() {
@@ -34,5 +104,52 @@ func init() {
 		TenantTotal,
 		TenantReconcileErrors,
 		TenantReconcileDuration,
+		DriftCorrected,
+		TenantsExpired,
+		TenantQuota,
+		TenantReady,
+		ReconcileTotal,
+		ChildApplies,
+		ProfileTenants,
 	)
 }
+
+// -----------------------------------------------------------------------------
+// reconcileOutcome classifies the result of a reconcile. Terminal errors come
+// from the resource configuration and are not retried.
+// -----------------------------------------------------------------------------
+func reconcileOutcome(result ctrl.Result, err error) string {
+	switch {
+	case errors.Is(err, reconcile.TerminalError(nil)):
+		return OutcomeInvalidConfig
+	case err != nil:
+		return OutcomeError
+	case !result.IsZero():
+		return OutcomeRequeue
+	default:
+		return OutcomeSuccess
+	}
+}
+
+// observeReconcile counts a reconcile of controller by outcome
+func observeReconcile(controller string, result ctrl.Result, err error) {
+	ReconcileTotal.WithLabelValues(controller, reconcileOutcome(result, err)).Inc()
+}
+
+// observeQuota exports the hard limits and usage of a tenant ResourceQuota
+func observeQuota(tenant string, rq *corev1.ResourceQuota) {
+	TenantQuota.DeletePartialMatch(prometheus.Labels{"tenant": tenant})
+
+	for name, q := range rq.Status.Hard {
+		TenantQuota.WithLabelValues(tenant, string(name), "hard").Set(q.AsApproximateFloat64())
+	}
+	for name, q := range rq.Status.Used {
+		TenantQuota.WithLabelValues(tenant, string(name), "used").Set(q.AsApproximateFloat64())
+	}
+}
+
+// forgetTenantMetrics drops the per-tenant series of a deleted tenant
+func forgetTenantMetrics(tenant string) {
+	TenantQuota.DeletePartialMatch(prometheus.Labels{"tenant": tenant})
+	TenantReady.DeleteLabelValues(tenant)
+}
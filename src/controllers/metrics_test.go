@@ -1,9 +1,15 @@
 package controllers
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func TestMetricsIncrement(t *testing.T) {
@@ -24,3 +30,65 @@ func TestMetricsIncrement(t *testing.T) {
 		t.Fatalf("expected TenantReconcileErrors = 1, got %v", val)
 	}
 }
+
+func TestReconcileOutcome(t *testing.T) {
+
+	cases := map[string]struct {
+		result ctrl.Result
+		err    error
+	}{
+		OutcomeSuccess:       {},
+		OutcomeRequeue:       {result: ctrl.Result{RequeueAfter: time.Minute}},
+		OutcomeError:         {err: errors.New("conflict")},
+		OutcomeInvalidConfig: {err: reconcile.TerminalError(errors.New("profile not found"))},
+	}
+
+	for want, c := range cases {
+		if got := reconcileOutcome(c.result, c.err); got != want {
+			t.Errorf("expected outcome %q, got %q", want, got)
+		}
+	}
+}
+
+func TestTenantQuotaMetrics(t *testing.T) {
+
+	rq := &corev1.ResourceQuota{
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				corev1.ResourceCPU:  resource.MustParse("4"),
+				corev1.ResourcePods: resource.MustParse("20"),
+			},
+			Used: corev1.ResourceList{
+				corev1.ResourceCPU:  resource.MustParse("1500m"),
+				corev1.ResourcePods: resource.MustParse("3"),
+			},
+		},
+	}
+
+	observeQuota("metrics-team", rq)
+
+	if val := testutil.ToFloat64(TenantQuota.WithLabelValues("metrics-team", "cpu", "used")); val != 1.5 {
+		t.Fatalf("expected cpu used = 1.5, got %v", val)
+	}
+	if val := testutil.ToFloat64(TenantQuota.WithLabelValues("metrics-team", "pods", "hard")); val != 20 {
+		t.Fatalf("expected pods hard = 20, got %v", val)
+	}
+
+	TenantReady.WithLabelValues("metrics-team").Set(1)
+	forgetTenantMetrics("metrics-team")
+
+	if n := testutil.CollectAndCount(TenantQuota) + testutil.CollectAndCount(TenantReady); n != 0 {
+		t.Fatalf("expected no series left for a deleted tenant, got %d", n)
+	}
+}
+
+func TestRecordDriftWithoutRecorder(t *testing.T) {
+	before := testutil.ToFloat64(DriftCorrected.WithLabelValues("LimitRange"))
+
+	// Reconcilers built without a recorder only count the correction
+	recordDrift(nil, &corev1.Namespace{}, "LimitRange", "team-a", "tenant-limits")
+
+	if val := testutil.ToFloat64(DriftCorrected.WithLabelValues("LimitRange")); val != before+1 {
+		t.Fatalf("expected DriftCorrected = %v, got %v", before+1, val)
+	}
+}
@@ -2,30 +2,64 @@ package controllers
 
 import (
 	"context"
+	"slices"
+	"sync"
 
 	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/audit"
+	"github.com/tngs/namespace-operator/config"
+	"github.com/tngs/namespace-operator/features"
+	"github.com/tngs/namespace-operator/tracing"
+
+	"go.opentelemetry.io/otel/trace"
 
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+// tenantNamespaceIndex indexes Tenants by the namespace they manage. The
+// Tenant CRD declares the same selectable field, so uncached clients can use
+// it as a field selector too.
+const tenantNamespaceIndex = "spec.namespace"
+
 // -----------------------------------------------------------------------------
 // RBAC
 // -----------------------------------------------------------------------------
 
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="platform.example.com",resources=tenants,verbs=get;list;watch
-// +kubebuilder:rbac:groups="networking.k8s.io",resources=networkpolicies,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="platform.example.com",resources=tenants/finalizers,verbs=update
+// +kubebuilder:rbac:groups="networking.k8s.io",resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 type NetworkPolicyReconciler struct {
 	client.Client
+	Recorder record.EventRecorder
+
+	// Config is the operator configuration, the defaults when nil
+	Config *config.Store
+
+	// Features are the feature gates, the defaults when nil
+	Features *features.Gates
+
+	// Audit receives the changes made to the cluster, nil to disable
+	Audit audit.Sink
+
+	// deletedPolicies remembers managed NetworkPolicies deleted while their
+	// namespace was still live, so the next reconcile reports the restore.
+	deletedPolicies sync.Map
 }
 
 // -----------------------------------------------------------------------------
@@ -34,6 +68,20 @@ func (r *NetworkPolicyReconciler) Reconcile(
 	ctx context.Context,
 	req ctrl.Request,
 ) (ctrl.Result, error) {
+	ctx, span := tracing.Start(ctx, "NetworkPolicy.Reconcile", tracing.NamespaceKey.String(req.Name))
+	ctx = audit.NewContext(ctx, r.Audit, "networkpolicy")
+
+	result, err := r.reconcile(ctx, req)
+	observeReconcile("networkpolicy", result, err)
+	tracing.End(span, err)
+
+	return result, err
+}
+
+func (r *NetworkPolicyReconciler) reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
 
 	logger := log.FromContext(ctx)
 
@@ -42,11 +90,15 @@ func (r *NetworkPolicyReconciler) Reconcile(
 	// -------------------------------------------------------------------------
 	var ns corev1.Namespace
 	if err := r.Get(ctx, req.NamespacedName, &ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.forgetDeletedPolicies(req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
 	// Skip if namespace is terminating
 	if ns.DeletionTimestamp != nil {
+		r.forgetDeletedPolicies(ns.Name)
 		return ctrl.Result{}, nil
 	}
 
@@ -60,23 +112,43 @@ func (r *NetworkPolicyReconciler) Reconcile(
 	// -------------------------------------------------------------------------
 
 	var tenants platformv1alpha1.TenantList
-	if err := r.List(ctx, &tenants); err != nil {
+	if err := r.List(
+		ctx,
+		&tenants,
+		client.MatchingFields{tenantNamespaceIndex: ns.Name},
+	); err != nil {
 		logger.Error(err, "unable to list Tenants")
 		return ctrl.Result{}, err
 	}
 
+	opCfg := r.Config.Get()
+
 	var tenant *platformv1alpha1.Tenant
-	for i := range tenants.Items {
-		if tenants.Items[i].Spec.Namespace == ns.Name {
-			tenant = &tenants.Items[i]
-			break
-		}
+	if len(tenants.Items) > 0 {
+		tenant = &tenants.Items[0]
+	}
+
+	// Namespaces of Tenants of other shards are reconciled by other operator
+	// instances
+	owner := ns.Labels[TenantLabelKey]
+	var ownerLabels map[string]string
+	if tenant != nil {
+		owner, ownerLabels = tenant.Name, tenant.Labels
+	}
+	if !opCfg.Sharding.Owns(owner, ownerLabels) {
+		r.forgetDeletedPolicies(ns.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if tenant != nil {
+		trace.SpanFromContext(ctx).SetAttributes(tracing.TenantKey.String(tenant.Name))
+		ctx = audit.WithTenant(ctx, tenant.Name, tenant.Generation)
 	}
 
 	// -------------------------------------------------------------------------
 	// Build policies
 	// -------------------------------------------------------------------------
-	policies := r.buildPolicies(ns.Name, tenant)
+	policies := r.buildPolicies(ns.Name, tenant, opCfg.Network)
 
 	// -------------------------------------------------------------------------
 	// Apply policies (Server-Side Apply)
@@ -86,25 +158,147 @@ func (r *NetworkPolicyReconciler) Reconcile(
 		np.SetGroupVersionKind(
 			networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"),
 		)
+		np.Labels = objectLabels(tenant, np.Spec)
 
-		if err := r.Patch(
-			ctx,
-			np,
-			client.Apply,
-			client.FieldOwner("namespace-operator"),
-			client.ForceOwnership,
-		); err != nil &&
+		// Owned by the tenant so policies are garbage collected with it
+		if tenant != nil {
+			if err := controllerutil.SetControllerReference(tenant, np, r.Scheme()); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		res, err := applyObject(ctx, r.Client, np)
+		if err != nil &&
 			!apierrors.IsAlreadyExists(err) &&
 			!apierrors.IsNotFound(err) {
 
 			logger.Error(err, "unable to apply NetworkPolicy", "name", np.Name)
 			return ctrl.Result{}, err
 		}
+
+		// A missing policy only counts as drift when its deletion was observed,
+		// otherwise it is simply being created for the first time.
+		drifted := res.existed && res.changed &&
+			res.prevHash == np.Labels[SpecHashLabelKey]
+		if !res.existed {
+			_, drifted = r.deletedPolicies.LoadAndDelete(client.ObjectKeyFromObject(np))
+		}
+
+		if drifted {
+			var owner client.Object = &ns
+			if tenant != nil {
+				owner = tenant
+			}
+			recordDrift(r.Recorder, owner, "NetworkPolicy", ns.Name, np.Name)
+		}
+	}
+
+	// -------------------------------------------------------------------------
+	// Prune baseline policies no longer desired (custom rules, new baseline)
+	// -------------------------------------------------------------------------
+	if !r.Features.Enabled(features.Pruning) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.pruneBaselinePolicies(ctx, ns.Name, policies); err != nil {
+		logger.Error(err, "unable to prune NetworkPolicies")
+		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// pruneBaselinePolicies deletes the managed baseline policies not in desired
+func (r *NetworkPolicyReconciler) pruneBaselinePolicies(
+	ctx context.Context,
+	namespace string,
+	desired []*networkingv1.NetworkPolicy,
+) error {
+
+	for _, name := range []string{defaultDenyIngressPolicy, defaultDenyEgressPolicy} {
+		if slices.ContainsFunc(desired, func(np *networkingv1.NetworkPolicy) bool {
+			return np.Name == name
+		}) {
+			continue
+		}
+
+		// Our own deletions are not drift
+		key := client.ObjectKey{Namespace: namespace, Name: name}
+		r.deletedPolicies.Delete(key)
+
+		np := &networkingv1.NetworkPolicy{}
+		if err := r.Get(ctx, key, np); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if np.Labels[ManagedByLabelKey] != ManagedByLabelValue {
+			continue
+		}
+
+		if err := r.Delete(ctx, np); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		audit.Record(ctx, audit.Delete, AuditReasonPruned, np, nil)
+	}
+
+	return nil
+}
+
+// forgetDeletedPolicies drops deletions recorded for a terminating namespace.
+func (r *NetworkPolicyReconciler) forgetDeletedPolicies(namespace string) {
+	r.deletedPolicies.Range(func(k, _ any) bool {
+		if k.(client.ObjectKey).Namespace == namespace {
+			r.deletedPolicies.Delete(k)
+		}
+		return true
+	})
+}
+
+// -----------------------------------------------------------------------------
+// policyEventHandler enqueues the namespace of a managed NetworkPolicy on any
+// change and records deletions for drift reporting.
+// -----------------------------------------------------------------------------
+func (r *NetworkPolicyReconciler) policyEventHandler() handler.EventHandler {
+
+	enqueue := func(
+		obj client.Object,
+		q workqueue.TypedRateLimitingInterface[reconcile.Request],
+	) {
+		q.Add(reconcile.Request{
+			NamespacedName: client.ObjectKey{Name: obj.GetNamespace()},
+		})
+	}
+
+	return handler.Funcs{
+		CreateFunc: func(
+			_ context.Context,
+			e event.CreateEvent,
+			q workqueue.TypedRateLimitingInterface[reconcile.Request],
+		) {
+			enqueue(e.Object, q)
+		},
+		UpdateFunc: func(
+			_ context.Context,
+			e event.UpdateEvent,
+			q workqueue.TypedRateLimitingInterface[reconcile.Request],
+		) {
+			enqueue(e.ObjectNew, q)
+		},
+		DeleteFunc: func(
+			_ context.Context,
+			e event.DeleteEvent,
+			q workqueue.TypedRateLimitingInterface[reconcile.Request],
+		) {
+			r.deletedPolicies.Store(client.ObjectKeyFromObject(e.Object), struct{}{})
+			enqueue(e.Object, q)
+		},
+	}
+}
+
 // -----------------------------------------------------------------------------
 
 func (r *NetworkPolicyReconciler) SetupWithManager(
@@ -115,7 +309,7 @@ func (r *NetworkPolicyReconciler) SetupWithManager(
 	if err := mgr.GetFieldIndexer().IndexField(
 		context.Background(),
 		&platformv1alpha1.Tenant{},
-		"spec.namespace",
+		tenantNamespaceIndex,
 		func(obj client.Object) []string {
 			tenant := obj.(*platformv1alpha1.Tenant)
 			if tenant.Spec.Namespace == "" {
@@ -127,8 +321,17 @@ func (r *NetworkPolicyReconciler) SetupWithManager(
 		return err
 	}
 
+	// Only managed namespaces are cached; of their updates, only label,
+	// annotation and deletion changes matter
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Namespace{}).
+		For(&corev1.Namespace{}, builder.WithPredicates(managedByPredicate, namespaceChangedPredicate)).
+		WithOptions(controllerOptions(r.Config.Get().Controllers.NetworkPolicy)).
+		// Restore managed policies as soon as they are edited or deleted
+		Watches(
+			&networkingv1.NetworkPolicy{},
+			r.policyEventHandler(),
+			builder.WithPredicates(managedByPredicate),
+		).
 		Watches(
 			&platformv1alpha1.Tenant{},
 			handler.EnqueueRequestsFromMapFunc(
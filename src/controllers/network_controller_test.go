@@ -7,10 +7,12 @@ import (
 	. "github.com/onsi/gomega"
 
 	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/config"
 
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,7 +30,8 @@ func TestNetworkPolicyReconciler_DefaultDeny(t *testing.T) {
 	g.Expect(err).NotTo(HaveOccurred())
 
 	reconciler := &NetworkPolicyReconciler{
-		Client: k8sClient,
+		Client:   k8sClient,
+		Recorder: record.NewFakeRecorder(10),
 	}
 
 	// Create namespace managed by operator
@@ -80,7 +83,8 @@ func TestNetworkPolicyReconciler_CustomNetwork(t *testing.T) {
 	g.Expect(err).NotTo(HaveOccurred())
 
 	reconciler := &NetworkPolicyReconciler{
-		Client: k8sClient,
+		Client:   k8sClient,
+		Recorder: record.NewFakeRecorder(10),
 	}
 
 	// Create namespace managed by operator
@@ -157,3 +161,40 @@ func TestNetworkPolicyReconciler_CustomNetwork(t *testing.T) {
 		npEgress,
 	)).To(Succeed())
 }
+
+// -----------------------------------------------------------------------------
+// Configured baseline test
+// -----------------------------------------------------------------------------
+func TestBuildPolicies_Baseline(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &NetworkPolicyReconciler{}
+	network := config.Default().Network
+	network.DNS = config.DNSConfig{
+		Namespace:   "dns",
+		PodSelector: map[string]string{"k8s-app": "coredns"},
+		Port:        5353,
+	}
+
+	names := func(policies []*networkingv1.NetworkPolicy) []string {
+		var out []string
+		for _, np := range policies {
+			out = append(out, np.Name)
+		}
+		return out
+	}
+
+	policies := r.buildPolicies("team-a", nil, network)
+	g.Expect(names(policies)).To(Equal([]string{defaultDenyIngressPolicy, defaultDenyEgressPolicy}))
+
+	egress := policies[1].Spec.Egress[0]
+	g.Expect(egress.To[0].NamespaceSelector.MatchLabels).To(HaveKeyWithValue("kubernetes.io/metadata.name", "dns"))
+	g.Expect(egress.To[0].PodSelector.MatchLabels).To(HaveKeyWithValue("k8s-app", "coredns"))
+	g.Expect(egress.Ports[0].Port.IntValue()).To(Equal(5353))
+
+	network.Baseline = config.BaselineDenyIngress
+	g.Expect(names(r.buildPolicies("team-a", nil, network))).To(Equal([]string{defaultDenyIngressPolicy}))
+
+	network.Baseline = config.BaselineNone
+	g.Expect(r.buildPolicies("team-a", nil, network)).To(BeEmpty())
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/config"
 
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -9,12 +10,21 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// Names of the baseline policies, pruned when the baseline no longer wants them
+const (
+	defaultDenyIngressPolicy = "default-deny-ingress"
+	defaultDenyEgressPolicy  = "default-deny-egress"
+)
+
 // -----------------------------------------------------------------------------
-// buildPolicies builds NetworkPolicies for a namespace
+// buildPolicies builds NetworkPolicies for a namespace: the tenant custom
+// rules if any, the configured baseline otherwise.
+// Labels and owner references are set by the caller before applying.
 // -----------------------------------------------------------------------------
 func (r *NetworkPolicyReconciler) buildPolicies(
 	namespace string,
 	tenant *platformv1alpha1.Tenant,
+	network config.NetworkConfig,
 ) []*networkingv1.NetworkPolicy {
 
 	// -------------------------------------------------------------
@@ -32,9 +42,6 @@ func (r *NetworkPolicyReconciler) buildPolicies(
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "custom-ingress",
 					Namespace: namespace,
-					Labels: map[string]string{
-						ManagedByLabelKey: ManagedByLabelValue,
-					},
 				},
 				Spec: networkingv1.NetworkPolicySpec{
 					PodSelector: metav1.LabelSelector{},
@@ -85,9 +92,6 @@ func (r *NetworkPolicyReconciler) buildPolicies(
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "custom-egress",
 					Namespace: namespace,
-					Labels: map[string]string{
-						ManagedByLabelKey: ManagedByLabelValue,
-					},
 				},
 				Spec: networkingv1.NetworkPolicySpec{
 					PodSelector: metav1.LabelSelector{},
@@ -137,18 +141,15 @@ func (r *NetworkPolicyReconciler) buildPolicies(
 	}
 
 	// -------------------------------------------------------------
-	// Default deny + allow DNS
+	// Baseline: deny ingress, and deny egress but allow DNS
 	// -------------------------------------------------------------
-	return []*networkingv1.NetworkPolicy{
+	var policies []*networkingv1.NetworkPolicy
 
-		// Ingress deny
-		{
+	if network.Baseline == config.BaselineDenyAll || network.Baseline == config.BaselineDenyIngress {
+		policies = append(policies, &networkingv1.NetworkPolicy{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      "default-deny-ingress",
+				Name:      defaultDenyIngressPolicy,
 				Namespace: namespace,
-				Labels: map[string]string{
-					ManagedByLabelKey: ManagedByLabelValue,
-				},
 			},
 			Spec: networkingv1.NetworkPolicySpec{
 				PodSelector: metav1.LabelSelector{},
@@ -156,16 +157,25 @@ func (r *NetworkPolicyReconciler) buildPolicies(
 					networkingv1.PolicyTypeIngress,
 				},
 			},
-		},
+		})
+	}
+
+	if network.Baseline == config.BaselineDenyAll {
+		dns := networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"kubernetes.io/metadata.name": network.DNS.Namespace,
+				},
+			},
+		}
+		if len(network.DNS.PodSelector) > 0 {
+			dns.PodSelector = &metav1.LabelSelector{MatchLabels: network.DNS.PodSelector}
+		}
 
-		// Egress deny but allow DNS
-		{
+		policies = append(policies, &networkingv1.NetworkPolicy{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      "default-deny-egress",
+				Name:      defaultDenyEgressPolicy,
 				Namespace: namespace,
-				Labels: map[string]string{
-					ManagedByLabelKey: ManagedByLabelValue,
-				},
 			},
 			Spec: networkingv1.NetworkPolicySpec{
 				PodSelector: metav1.LabelSelector{},
@@ -174,30 +184,24 @@ func (r *NetworkPolicyReconciler) buildPolicies(
 				},
 				Egress: []networkingv1.NetworkPolicyEgressRule{
 					{
-						To: []networkingv1.NetworkPolicyPeer{
-							{
-								NamespaceSelector: &metav1.LabelSelector{
-									MatchLabels: map[string]string{
-										"kubernetes.io/metadata.name": "kube-system",
-									},
-								},
-							},
-						},
+						To: []networkingv1.NetworkPolicyPeer{dns},
 						Ports: []networkingv1.NetworkPolicyPort{
 							{
 								Protocol: protocolPtr(corev1.ProtocolUDP),
-								Port:     intStrPtr(53),
+								Port:     intStrPtr(int(network.DNS.Port)),
 							},
 							{
 								Protocol: protocolPtr(corev1.ProtocolTCP),
-								Port:     intStrPtr(53),
+								Port:     intStrPtr(int(network.DNS.Port)),
 							},
 						},
 					},
 				},
 			},
-		},
+		})
 	}
+
+	return policies
 }
 
 // -----------------------------------------------------------------
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"github.com/tngs/namespace-operator/config"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// -----------------------------------------------------------------------------
+// controllerOptions builds the options of a controller from its configuration:
+// worker count, and a rate limiter combining per-object exponential backoff
+// with a token bucket shared by every object.
+// -----------------------------------------------------------------------------
+func controllerOptions(cc config.ControllerConfig) controller.Options {
+	rl := cc.RateLimiter
+	return controller.Options{
+		MaxConcurrentReconciles: cc.MaxConcurrentReconciles,
+		RateLimiter: workqueue.NewTypedMaxOfRateLimiter(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](
+				rl.BaseDelay.Duration,
+				rl.MaxDelay.Duration,
+			),
+			&workqueue.TypedBucketRateLimiter[reconcile.Request]{
+				Limiter: rate.NewLimiter(rate.Limit(rl.QPS), rl.Burst),
+			},
+		),
+	}
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tngs/namespace-operator/config"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestControllerOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	cc := config.ControllerConfig{
+		MaxConcurrentReconciles: 8,
+		RateLimiter: config.RateLimiterConfig{
+			BaseDelay: metav1.Duration{Duration: 10 * time.Millisecond},
+			MaxDelay:  metav1.Duration{Duration: 40 * time.Millisecond},
+			QPS:       1,
+			Burst:     2,
+		},
+	}
+
+	opts := controllerOptions(cc)
+	g.Expect(opts.MaxConcurrentReconciles).To(Equal(8))
+
+	limiter := opts.RateLimiter
+	failing := reconcile.Request{NamespacedName: types.NamespacedName{Name: "failing"}}
+
+	// Exponential backoff per object while the bucket burst lasts, then the
+	// bucket rate
+	g.Expect(limiter.When(failing)).To(Equal(10 * time.Millisecond))
+	g.Expect(limiter.When(failing)).To(Equal(20 * time.Millisecond))
+	g.Expect(limiter.When(failing)).To(BeNumerically(">", 500*time.Millisecond))
+
+	limiter.Forget(failing)
+	g.Expect(limiter.NumRequeues(failing)).To(BeZero())
+
+	// The shared bucket is empty: other objects wait for a token too
+	other := reconcile.Request{NamespacedName: types.NamespacedName{Name: "other"}}
+	g.Expect(limiter.When(other)).To(BeNumerically(">", 500*time.Millisecond))
+}
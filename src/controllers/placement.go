@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"encoding/json"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// -----------------------------------------------------------------------------
+// placementAnnotations returns the scheduler annotations pinning the tenant
+// namespace to the node pool of its profile. The node selector is enforced by
+// the PodNodeSelector plugin (or the placement webhook), which rejects pods
+// asking for conflicting node labels.
+// -----------------------------------------------------------------------------
+func placementAnnotations(
+	profile *platformv1alpha1.TenantProfile,
+) (map[string]string, error) {
+
+	annotations := map[string]string{}
+
+	if profile == nil || profile.Spec.Placement == nil {
+		return annotations, nil
+	}
+	placement := profile.Spec.Placement
+
+	if len(placement.NodeSelector) > 0 {
+		annotations[platformv1alpha1.NodeSelectorAnnotation] =
+			labels.Set(placement.NodeSelector).String()
+	}
+
+	if len(placement.Tolerations) > 0 {
+		data, err := json.Marshal(placement.Tolerations)
+		if err != nil {
+			return nil, err
+		}
+		annotations[platformv1alpha1.DefaultTolerationsAnnotation] = string(data)
+	}
+
+	return annotations, nil
+}
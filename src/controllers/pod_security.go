@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+)
+
+// Pod Security Admission namespace labels
+const (
+	PodSecurityLabelPrefix = "pod-security.kubernetes.io/"
+
+	podSecurityEnforceLabel        = PodSecurityLabelPrefix + "enforce"
+	podSecurityEnforceVersionLabel = PodSecurityLabelPrefix + "enforce-version"
+	podSecurityAuditLabel          = PodSecurityLabelPrefix + "audit"
+	podSecurityAuditVersionLabel   = PodSecurityLabelPrefix + "audit-version"
+	podSecurityWarnLabel           = PodSecurityLabelPrefix + "warn"
+	podSecurityWarnVersionLabel    = PodSecurityLabelPrefix + "warn-version"
+)
+
+// -----------------------------------------------------------------------------
+// podSecurityLabels computes the Pod Security Admission labels for a tenant
+// namespace: restricted by default, overridden by the profile and then by the
+// tenant. An enforce level below the profile minimum, or below
+// operatorMinimum for tenants without a profile, is raised to the minimum
+// (the webhook normally rejects it first) and reported through clamped.
+// -----------------------------------------------------------------------------
+func podSecurityLabels(
+	tenant *platformv1alpha1.Tenant,
+	profile *platformv1alpha1.TenantProfile,
+	operatorMinimum string,
+) (labels map[string]string, clamped bool) {
+
+	effective := platformv1alpha1.PodSecuritySpec{
+		Enforce: platformv1alpha1.PodSecurityLevelRestricted,
+		Audit:   platformv1alpha1.PodSecurityLevelRestricted,
+		Warn:    platformv1alpha1.PodSecurityLevelRestricted,
+	}
+
+	var minimum *platformv1alpha1.PodSecurityProfileSpec
+	if profile != nil {
+		minimum = profile.Spec.PodSecurity
+		if profile.Spec.PodSecurity != nil {
+			overridePodSecurity(&effective, &profile.Spec.PodSecurity.PodSecuritySpec)
+		}
+	}
+	overridePodSecurity(&effective, tenant.Spec.PodSecurity)
+
+	floor := operatorMinimum
+	if profile != nil {
+		floor = minimum.MinimumEnforceLevel()
+	}
+	if platformv1alpha1.PodSecurityLevelRank(effective.Enforce) <
+		platformv1alpha1.PodSecurityLevelRank(floor) {
+		effective.Enforce = floor
+		clamped = true
+	}
+
+	labels = map[string]string{
+		podSecurityEnforceLabel: effective.Enforce,
+		podSecurityAuditLabel:   effective.Audit,
+		podSecurityWarnLabel:    effective.Warn,
+	}
+
+	for key, version := range map[string]string{
+		podSecurityEnforceVersionLabel: effective.EnforceVersion,
+		podSecurityAuditVersionLabel:   effective.AuditVersion,
+		podSecurityWarnVersionLabel:    effective.WarnVersion,
+	} {
+		if version != "" {
+			labels[key] = version
+		}
+	}
+
+	return labels, clamped
+}
+
+// overridePodSecurity copies the fields set in src over dst.
+func overridePodSecurity(dst, src *platformv1alpha1.PodSecuritySpec) {
+	if src == nil {
+		return
+	}
+	if src.Enforce != "" {
+		dst.Enforce = src.Enforce
+	}
+	if src.EnforceVersion != "" {
+		dst.EnforceVersion = src.EnforceVersion
+	}
+	if src.Audit != "" {
+		dst.Audit = src.Audit
+	}
+	if src.AuditVersion != "" {
+		dst.AuditVersion = src.AuditVersion
+	}
+	if src.Warn != "" {
+		dst.Warn = src.Warn
+	}
+	if src.WarnVersion != "" {
+		dst.WarnVersion = src.WarnVersion
+	}
+}
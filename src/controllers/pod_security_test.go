@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"testing"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodSecurityLabelsMinimum(t *testing.T) {
+	g := NewWithT(t)
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec: platformv1alpha1.TenantSpec{
+			Namespace:   "team-a",
+			PodSecurity: &platformv1alpha1.PodSecuritySpec{Enforce: platformv1alpha1.PodSecurityLevelPrivileged},
+		},
+	}
+
+	// Without a profile the operator minimum applies
+	labels, clamped := podSecurityLabels(tenant, nil, platformv1alpha1.PodSecurityLevelBaseline)
+	g.Expect(clamped).To(BeTrue())
+	g.Expect(labels).To(HaveKeyWithValue(podSecurityEnforceLabel, platformv1alpha1.PodSecurityLevelBaseline))
+
+	labels, clamped = podSecurityLabels(tenant, nil, platformv1alpha1.PodSecurityLevelPrivileged)
+	g.Expect(clamped).To(BeFalse())
+	g.Expect(labels).To(HaveKeyWithValue(podSecurityEnforceLabel, platformv1alpha1.PodSecurityLevelPrivileged))
+
+	// With a profile, the profile minimum replaces it
+	profile := &platformv1alpha1.TenantProfile{
+		Spec: platformv1alpha1.TenantProfileSpec{
+			PodSecurity: &platformv1alpha1.PodSecurityProfileSpec{
+				MinimumEnforce: platformv1alpha1.PodSecurityLevelPrivileged,
+			},
+		},
+	}
+	labels, clamped = podSecurityLabels(tenant, profile, platformv1alpha1.PodSecurityLevelRestricted)
+	g.Expect(clamped).To(BeFalse())
+	g.Expect(labels).To(HaveKeyWithValue(podSecurityEnforceLabel, platformv1alpha1.PodSecurityLevelPrivileged))
+}
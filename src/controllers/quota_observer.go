@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// -----------------------------------------------------------------------------
+// quotaObserver keeps TenantQuota up to date from ResourceQuota informer
+// events, so quota usage following pod churn does not trigger a reconcile.
+// owns filters the tenants of this shard.
+// -----------------------------------------------------------------------------
+type quotaObserver struct {
+	owns func(tenant string) bool
+}
+
+func (o quotaObserver) handler() toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			o.observe(obj)
+		},
+		UpdateFunc: func(_, newObj any) {
+			o.observe(newObj)
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if tenant, _ := o.tenantQuota(obj); tenant != "" {
+				TenantQuota.DeletePartialMatch(prometheus.Labels{"tenant": tenant})
+			}
+		},
+	}
+}
+
+// observe exports the quota of a tenant, ignoring every other ResourceQuota
+func (o quotaObserver) observe(obj any) {
+	if tenant, rq := o.tenantQuota(obj); tenant != "" {
+		observeQuota(tenant, rq)
+	}
+}
+
+// tenantQuota returns the quota and its tenant when obj is the tenant-quota
+// of a tenant of this shard, an empty tenant otherwise
+func (o quotaObserver) tenantQuota(obj any) (string, *corev1.ResourceQuota) {
+	rq, ok := obj.(*corev1.ResourceQuota)
+	if !ok || rq.Name != ResourceQuotaName || rq.Labels[ManagedByLabelKey] != ManagedByLabelValue {
+		return "", nil
+	}
+
+	tenant := rq.Labels[TenantLabelKey]
+	if tenant == "" || (o.owns != nil && !o.owns(tenant)) {
+		return "", nil
+	}
+	return tenant, rq
+}
+
+// -----------------------------------------------------------------------------
+// quotaChangedPredicate drops ResourceQuota updates that change neither the
+// spec nor the labels: status updates follow every pod start and stop and
+// never change what the operator applies. ResourceQuotas have no generation.
+// -----------------------------------------------------------------------------
+var quotaChangedPredicate = predicate.Or(
+	predicate.LabelChangedPredicate{},
+	predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			old, okOld := e.ObjectOld.(*corev1.ResourceQuota)
+			rq, okNew := e.ObjectNew.(*corev1.ResourceQuota)
+			return !okOld || !okNew || !equality.Semantic.DeepEqual(old.Spec, rq.Spec)
+		},
+	},
+)
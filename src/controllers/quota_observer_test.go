@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestQuotaObserver(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Cleanup(func() {
+		forgetTenantMetrics("observed-team")
+		forgetTenantMetrics("other-shard")
+	})
+
+	quota := func(name, tenant, usedPods string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: tenant,
+				Labels:    map[string]string{ManagedByLabelKey: ManagedByLabelValue, TenantLabelKey: tenant},
+			},
+			Status: corev1.ResourceQuotaStatus{
+				Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse(usedPods)},
+			},
+		}
+	}
+
+	h := quotaObserver{owns: func(tenant string) bool { return tenant != "other-shard" }}.handler()
+
+	h.OnAdd(quota(ResourceQuotaName, "observed-team", "1"), true)
+	h.OnUpdate(quota(ResourceQuotaName, "observed-team", "1"), quota(ResourceQuotaName, "observed-team", "3"))
+	g.Expect(testutil.ToFloat64(TenantQuota.WithLabelValues("observed-team", "pods", "used"))).To(Equal(3.0))
+
+	// Class quotas and tenants of other shards are not exported
+	h.OnUpdate(nil, quota("tenant-class-quota", "observed-team", "7"))
+	h.OnAdd(quota(ResourceQuotaName, "other-shard", "2"), true)
+	g.Expect(testutil.ToFloat64(TenantQuota.WithLabelValues("observed-team", "pods", "used"))).To(Equal(3.0))
+	g.Expect(testutil.CollectAndCount(TenantQuota, "namespace_operator_tenant_quota")).To(Equal(1))
+
+	// Deleted quotas are no longer exported, also when the deletion was missed
+	h.OnDelete(toolscache.DeletedFinalStateUnknown{
+		Key: "observed-team/" + ResourceQuotaName,
+		Obj: quota(ResourceQuotaName, "observed-team", "3"),
+	})
+	g.Expect(testutil.CollectAndCount(TenantQuota, "namespace_operator_tenant_quota")).To(BeZero())
+}
+
+func TestQuotaChangedPredicate(t *testing.T) {
+	g := NewWithT(t)
+
+	old := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: ResourceQuotaName, Labels: map[string]string{"a": "b"}},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+		},
+	}
+
+	update := func(mutate func(*corev1.ResourceQuota)) bool {
+		rq := old.DeepCopy()
+		mutate(rq)
+		return quotaChangedPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: rq})
+	}
+
+	g.Expect(update(func(rq *corev1.ResourceQuota) {
+		rq.Status.Used = corev1.ResourceList{corev1.ResourcePods: resource.MustParse("4")}
+		rq.ResourceVersion = "2"
+	})).To(BeFalse())
+
+	g.Expect(update(func(rq *corev1.ResourceQuota) {
+		rq.Spec.Hard[corev1.ResourcePods] = resource.MustParse("20")
+	})).To(BeTrue())
+
+	g.Expect(update(func(rq *corev1.ResourceQuota) {
+		rq.Labels["a"] = "c"
+	})).To(BeTrue())
+
+	g.Expect(quotaChangedPredicate.Delete(event.DeleteEvent{Object: old})).To(BeTrue())
+}
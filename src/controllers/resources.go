@@ -0,0 +1,210 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/audit"
+	"github.com/tngs/namespace-operator/features"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+// -----------------------------------------------------------------------------
+// renderResourceTemplate renders a profile resource template into an object
+// for the tenant namespace. Structured objects have their string values
+// rendered; YAML templates are rendered as a whole and then decoded.
+// -----------------------------------------------------------------------------
+func renderResourceTemplate(
+	t platformv1alpha1.ResourceTemplate,
+	tenant *platformv1alpha1.Tenant,
+	data templateData,
+) (*unstructured.Unstructured, error) {
+
+	obj := map[string]any{}
+
+	switch {
+	case t.Object != nil:
+		if err := json.Unmarshal(t.Object.Raw, &obj); err != nil {
+			return nil, err
+		}
+		rendered, err := renderValues(t.Name, obj, data)
+		if err != nil {
+			return nil, err
+		}
+		obj = rendered.(map[string]any)
+
+	case t.Template != "":
+		out, err := renderTemplate(t.Name, t.Template, data)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal([]byte(out), &obj); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("one of object or template must be set")
+	}
+
+	u := &unstructured.Unstructured{Object: obj}
+	if u.GetAPIVersion() == "" || u.GetKind() == "" || u.GetName() == "" {
+		return nil, fmt.Errorf("apiVersion, kind and metadata.name are required")
+	}
+
+	u.SetNamespace(tenant.Spec.Namespace)
+
+	// Labels from the template are kept; the operator labels take precedence
+	labels := u.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range objectLabels(tenant, u.Object) {
+		labels[k] = v
+	}
+	u.SetLabels(labels)
+
+	return u, nil
+}
+
+// renderValues renders every string found in a decoded JSON value.
+func renderValues(name string, value any, data templateData) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return renderTemplate(name, v, data)
+
+	case map[string]any:
+		for key, item := range v {
+			rendered, err := renderValues(name, item, data)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = rendered
+		}
+		return v, nil
+
+	case []any:
+		for i, item := range v {
+			rendered, err := renderValues(name, item, data)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = rendered
+		}
+		return v, nil
+	}
+
+	return value, nil
+}
+
+// -----------------------------------------------------------------------------
+// reconcileResources applies the profile resource templates into the tenant
+// namespace and deletes objects listed in the tenant status that are no
+// longer rendered. It returns the references of the applied objects.
+// Templates are applied through the unstructured client, so any namespaced
+// kind works as long as the operator is allowed to manage it (see the
+// aggregated ClusterRole in the chart).
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) reconcileResources(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	profile *platformv1alpha1.TenantProfile,
+	applied bool,
+) ([]platformv1alpha1.ResourceReference, error) {
+
+	var templates []platformv1alpha1.ResourceTemplate
+	if profile != nil {
+		templates = profile.Spec.Resources
+	}
+
+	data := newTemplateData(tenant, profile)
+
+	desired := map[platformv1alpha1.ResourceReference]struct{}{}
+	refs := make([]platformv1alpha1.ResourceReference, 0, len(templates))
+
+	for _, t := range templates {
+
+		u, err := renderResourceTemplate(t, tenant, data)
+		if err != nil {
+			return nil, fmt.Errorf("resource template %s: %w", t.Name, err)
+		}
+
+		namespaced, err := r.IsObjectNamespaced(u)
+		if err != nil {
+			return nil, fmt.Errorf("resource template %s: %w", t.Name, err)
+		}
+		if !namespaced {
+			return nil, fmt.Errorf("resource template %s: %s is cluster-scoped", t.Name, u.GetKind())
+		}
+
+		if err := controllerutil.SetControllerReference(tenant, u, r.Scheme); err != nil {
+			return nil, err
+		}
+
+		res, err := applyObject(ctx, r.Client, u)
+		if err != nil {
+			return nil, fmt.Errorf("resource template %s: %w", t.Name, err)
+		}
+		if res.drifted(u.GetLabels()[SpecHashLabelKey], applied) {
+			recordDrift(r.Recorder, tenant, u.GetKind(), u.GetNamespace(), u.GetName())
+		}
+
+		ref := platformv1alpha1.ResourceReference{
+			APIVersion: u.GetAPIVersion(),
+			Kind:       u.GetKind(),
+			Name:       u.GetName(),
+		}
+		if _, ok := desired[ref]; !ok {
+			desired[ref] = struct{}{}
+			refs = append(refs, ref)
+		}
+	}
+
+	// -------------------------------------------------------------------------
+	// Prune objects applied previously but no longer rendered
+	// -------------------------------------------------------------------------
+	// Without pruning they stay listed, to be pruned once it is enabled.
+	pruning := r.Features.Enabled(features.Pruning)
+	for _, ref := range tenant.Status.Resources {
+		if _, ok := desired[ref]; ok {
+			continue
+		}
+		if !pruning {
+			desired[ref] = struct{}{}
+			refs = append(refs, ref)
+			continue
+		}
+
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(ref.APIVersion)
+		u.SetKind(ref.Kind)
+		u.SetNamespace(tenant.Spec.Namespace)
+		u.SetName(ref.Name)
+
+		if err := r.Delete(ctx, u); err == nil {
+			audit.Record(ctx, audit.Delete, AuditReasonPruned, u, nil)
+		} else if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return nil, err
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].APIVersion != refs[j].APIVersion {
+			return refs[i].APIVersion < refs[j].APIVersion
+		}
+		if refs[i].Kind != refs[j].Kind {
+			return refs[i].Kind < refs[j].Kind
+		}
+		return refs[i].Name < refs[j].Name
+	})
+
+	return refs, nil
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"testing"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRenderResourceTemplate(t *testing.T) {
+	g := NewWithT(t)
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec:       platformv1alpha1.TenantSpec{Namespace: "team-a"},
+	}
+	data := newTemplateData(tenant, nil)
+
+	// -------------------------------------------------------------------------
+	// Structured object: string values are rendered, namespace is forced
+	// -------------------------------------------------------------------------
+	u, err := renderResourceTemplate(platformv1alpha1.ResourceTemplate{
+		Name: "pdb",
+		Object: &runtime.RawExtension{Raw: []byte(`{
+			"apiVersion": "policy/v1",
+			"kind": "PodDisruptionBudget",
+			"metadata": {"name": "{{ .Tenant.Name }}-pdb", "namespace": "elsewhere", "labels": {"team": "a"}},
+			"spec": {"maxUnavailable": 1}
+		}`)},
+	}, tenant, data)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(u.GetName()).To(Equal("team-a-pdb"))
+	g.Expect(u.GetNamespace()).To(Equal("team-a"))
+	g.Expect(u.GetLabels()).To(HaveKeyWithValue("team", "a"))
+	g.Expect(u.GetLabels()).To(HaveKeyWithValue(TenantLabelKey, "team-a"))
+	g.Expect(u.GetLabels()).To(HaveKey(SpecHashLabelKey))
+
+	// -------------------------------------------------------------------------
+	// YAML template
+	// -------------------------------------------------------------------------
+	u, err = renderResourceTemplate(platformv1alpha1.ResourceTemplate{
+		Name: "role",
+		Template: `apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: {{ .Namespace }}-reader
+rules: []
+`,
+	}, tenant, data)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(u.GetKind()).To(Equal("Role"))
+	g.Expect(u.GetName()).To(Equal("team-a-reader"))
+
+	// -------------------------------------------------------------------------
+	// Missing kind
+	// -------------------------------------------------------------------------
+	_, err = renderResourceTemplate(platformv1alpha1.ResourceTemplate{
+		Name:     "broken",
+		Template: "metadata:\n  name: x\n",
+	}, tenant, data)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	"github.com/robfig/cron/v3"
+)
+
+// scheduleLookback bounds the search for the last sleep/wake time. A week
+// plus a day covers weekly schedules.
+const scheduleLookback = 8 * 24 * time.Hour
+
+// -----------------------------------------------------------------------------
+// scheduleState is the result of evaluating a hibernation schedule: whether
+// the tenant sleeps now and when the state changes next.
+// -----------------------------------------------------------------------------
+type scheduleState struct {
+	Asleep bool
+	Next   time.Time
+
+	// WokenUntil is set while the wake-until annotation overrides the schedule
+	WokenUntil *time.Time
+}
+
+// -----------------------------------------------------------------------------
+// evaluateSchedule reports whether the tenant is inside a sleep window at
+// now: asleep when its last sleep time is more recent than its last wake
+// time. The wake-until annotation keeps the tenant awake until that time.
+// -----------------------------------------------------------------------------
+func evaluateSchedule(
+	tenant *platformv1alpha1.Tenant,
+	now time.Time,
+) (scheduleState, error) {
+
+	var state scheduleState
+
+	schedule := tenant.Spec.Schedule
+	if schedule == nil {
+		return state, nil
+	}
+
+	location := time.UTC
+	if schedule.TimeZone != "" {
+		loc, err := time.LoadLocation(schedule.TimeZone)
+		if err != nil {
+			return state, fmt.Errorf("invalid time zone %q: %w", schedule.TimeZone, err)
+		}
+		location = loc
+	}
+
+	sleep, err := cron.ParseStandard(schedule.Sleep)
+	if err != nil {
+		return state, fmt.Errorf("invalid sleep schedule %q: %w", schedule.Sleep, err)
+	}
+	wake, err := cron.ParseStandard(schedule.Wake)
+	if err != nil {
+		return state, fmt.Errorf("invalid wake schedule %q: %w", schedule.Wake, err)
+	}
+
+	local := now.In(location)
+
+	lastSleep := lastActivation(sleep, local)
+	lastWake := lastActivation(wake, local)
+	state.Asleep = !lastSleep.IsZero() && lastSleep.After(lastWake)
+
+	state.Next = sleep.Next(local)
+	if next := wake.Next(local); next.Before(state.Next) {
+		state.Next = next
+	}
+
+	// -------------------------------------------------------------------------
+	// Manual override
+	// -------------------------------------------------------------------------
+	if v := tenant.Annotations[platformv1alpha1.WakeUntilAnnotation]; v != "" {
+		until, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return state, fmt.Errorf("invalid %s annotation %q: %w",
+				platformv1alpha1.WakeUntilAnnotation, v, err)
+		}
+		if now.Before(until) {
+			state.WokenUntil = &until
+			state.Asleep = false
+			if until.Before(state.Next) {
+				state.Next = until
+			}
+		}
+	}
+
+	return state, nil
+}
+
+// lastActivation returns the last time at or before now matching schedule,
+// or the zero time if there is none within scheduleLookback.
+func lastActivation(schedule cron.Schedule, now time.Time) time.Time {
+	var last time.Time
+	for t := schedule.Next(now.Add(-scheduleLookback)); !t.After(now); t = schedule.Next(t) {
+		last = t
+	}
+	return last
+}
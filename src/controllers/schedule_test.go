@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestEvaluateSchedule(t *testing.T) {
+	g := NewWithT(t)
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev"},
+		Spec: platformv1alpha1.TenantSpec{
+			Namespace: "dev",
+			Schedule: &platformv1alpha1.HibernationSchedule{
+				Sleep:    "0 19 * * 1-5",
+				Wake:     "0 7 * * 1-5",
+				TimeZone: "Europe/Paris",
+			},
+		},
+	}
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Wednesday 10:00: awake until 19:00
+	state, err := evaluateSchedule(tenant, time.Date(2026, 10, 14, 10, 0, 0, 0, paris))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(state.Asleep).To(BeFalse())
+	g.Expect(state.Next).To(BeTemporally("==", time.Date(2026, 10, 14, 19, 0, 0, 0, paris)))
+
+	// Wednesday 23:00: asleep until Thursday 07:00
+	state, err = evaluateSchedule(tenant, time.Date(2026, 10, 14, 23, 0, 0, 0, paris))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(state.Asleep).To(BeTrue())
+	g.Expect(state.Next).To(BeTemporally("==", time.Date(2026, 10, 15, 7, 0, 0, 0, paris)))
+
+	// Saturday noon: asleep until Monday 07:00
+	saturday := time.Date(2026, 10, 17, 12, 0, 0, 0, paris)
+	state, err = evaluateSchedule(tenant, saturday)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(state.Asleep).To(BeTrue())
+	g.Expect(state.Next).To(BeTemporally("==", time.Date(2026, 10, 19, 7, 0, 0, 0, paris)))
+
+	// Woken up manually for two hours
+	until := saturday.Add(2 * time.Hour)
+	tenant.Annotations = map[string]string{
+		platformv1alpha1.WakeUntilAnnotation: until.Format(time.RFC3339),
+	}
+	state, err = evaluateSchedule(tenant, saturday)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(state.Asleep).To(BeFalse())
+	g.Expect(state.Next).To(BeTemporally("==", until))
+
+	// Override expired
+	state, err = evaluateSchedule(tenant, until.Add(time.Minute))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(state.Asleep).To(BeTrue())
+}
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"github.com/tngs/namespace-operator/config"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// -----------------------------------------------------------------------------
+// tenantShardPredicate drops the events of Tenants reconciled by another
+// shard. An update moving a Tenant between shards reaches both, so the shard
+// it leaves drops its metrics.
+// -----------------------------------------------------------------------------
+func tenantShardPredicate(sharding config.ShardingConfig) predicate.Predicate {
+
+	owns := func(obj client.Object) bool {
+		return sharding.Owns(obj.GetName(), obj.GetLabels())
+	}
+
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return owns(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return owns(e.ObjectOld) || owns(e.ObjectNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return owns(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return owns(e.Object) },
+	}
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"testing"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/config"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestTenantShardPredicate(t *testing.T) {
+	g := NewWithT(t)
+
+	sharding := config.ShardingConfig{Shards: 2, Shard: 1}
+	pred := tenantShardPredicate(sharding)
+
+	tenantIn := func(shard string) *platformv1alpha1.Tenant {
+		return &platformv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "team-a",
+				Labels: map[string]string{platformv1alpha1.ShardLabel: shard},
+			},
+		}
+	}
+
+	g.Expect(pred.Create(event.CreateEvent{Object: tenantIn("1")})).To(BeTrue())
+	g.Expect(pred.Create(event.CreateEvent{Object: tenantIn("0")})).To(BeFalse())
+	g.Expect(pred.Delete(event.DeleteEvent{Object: tenantIn("0")})).To(BeFalse())
+
+	// Moving into or out of the shard reaches it
+	g.Expect(pred.Update(event.UpdateEvent{ObjectOld: tenantIn("0"), ObjectNew: tenantIn("1")})).To(BeTrue())
+	g.Expect(pred.Update(event.UpdateEvent{ObjectOld: tenantIn("1"), ObjectNew: tenantIn("0")})).To(BeTrue())
+	g.Expect(pred.Update(event.UpdateEvent{ObjectOld: tenantIn("0"), ObjectNew: tenantIn("0")})).To(BeFalse())
+}
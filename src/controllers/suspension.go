@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/audit"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// -----------------------------------------------------------------------------
+// reconcileSuspension scales the Deployments and StatefulSets of a suspended
+// tenant to zero and suspends its CronJobs, remembering the original values
+// in annotations; they are restored once the tenant is resumed. New pods are
+// blocked by the quota (pods: 0) while suspended, so workloads created in the
+// meantime do not need to be tracked, nor workloads watched: they are listed
+// through the API reader, only when the tenant is reconciled, instead of
+// caching every workload of the cluster. A tenant that is not suspended only
+// lists their metadata, until one carries a suspension annotation.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) reconcileSuspension(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	suspended bool,
+) error {
+
+	inNamespace := client.InNamespace(tenant.Spec.Namespace)
+
+	if !suspended {
+		found, err := r.hasSuspendedWorkloads(ctx, inNamespace)
+		if err != nil || !found {
+			return err
+		}
+	}
+
+	var deployments appsv1.DeploymentList
+	if err := r.reader().List(ctx, &deployments, inNamespace); err != nil {
+		return err
+	}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if err := r.suspendReplicas(ctx, d, &d.Spec.Replicas, suspended); err != nil {
+			return err
+		}
+	}
+
+	var statefulSets appsv1.StatefulSetList
+	if err := r.reader().List(ctx, &statefulSets, inNamespace); err != nil {
+		return err
+	}
+	for i := range statefulSets.Items {
+		s := &statefulSets.Items[i]
+		if err := r.suspendReplicas(ctx, s, &s.Spec.Replicas, suspended); err != nil {
+			return err
+		}
+	}
+
+	var cronJobs batchv1.CronJobList
+	if err := r.reader().List(ctx, &cronJobs, inNamespace); err != nil {
+		return err
+	}
+	for i := range cronJobs.Items {
+		if err := r.suspendCronJob(ctx, &cronJobs.Items[i], suspended); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// hasSuspendedWorkloads reports whether a workload still carries a suspension
+// annotation. Only the metadata of the workloads is read.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) hasSuspendedWorkloads(
+	ctx context.Context,
+	inNamespace client.InNamespace,
+) (bool, error) {
+
+	for _, kind := range []schema.GroupVersionKind{
+		appsv1.SchemeGroupVersion.WithKind("DeploymentList"),
+		appsv1.SchemeGroupVersion.WithKind("StatefulSetList"),
+		batchv1.SchemeGroupVersion.WithKind("CronJobList"),
+	} {
+		workloads := &metav1.PartialObjectMetadataList{}
+		workloads.SetGroupVersionKind(kind)
+		if err := r.reader().List(ctx, workloads, inNamespace); err != nil {
+			return false, err
+		}
+		for _, w := range workloads.Items {
+			if _, ok := w.Annotations[SuspendedReplicasAnnotationKey]; ok {
+				return true, nil
+			}
+			if _, ok := w.Annotations[SuspendedCronJobAnnotationKey]; ok {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// -----------------------------------------------------------------------------
+// suspendReplicas scales a workload to zero, saving its replica count, or
+// restores the saved count.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) suspendReplicas(
+	ctx context.Context,
+	obj client.Object,
+	replicas **int32,
+	suspended bool,
+) error {
+
+	original := obj.DeepCopyObject().(client.Object)
+	annotations := obj.GetAnnotations()
+	saved, isSaved := annotations[SuspendedReplicasAnnotationKey]
+
+	switch {
+	case suspended && !isSaved:
+		current := int32(1)
+		if *replicas != nil {
+			current = **replicas
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[SuspendedReplicasAnnotationKey] = strconv.Itoa(int(current))
+		*replicas = ptr.To[int32](0)
+
+	case suspended:
+		*replicas = ptr.To[int32](0)
+
+	case isSaved:
+		count, err := strconv.Atoi(saved)
+		if err != nil {
+			count = 1
+		}
+		delete(annotations, SuspendedReplicasAnnotationKey)
+		*replicas = ptr.To(int32(count))
+
+	default:
+		return nil
+	}
+
+	obj.SetAnnotations(annotations)
+
+	if equality.Semantic.DeepEqual(original, obj) {
+		return nil
+	}
+
+	if err := r.Patch(ctx, obj, client.MergeFrom(original)); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.Update, suspensionReason(suspended), original, obj)
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// suspendCronJob suspends a CronJob, saving its suspend flag, or restores it.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) suspendCronJob(
+	ctx context.Context,
+	cj *batchv1.CronJob,
+	suspended bool,
+) error {
+
+	original := cj.DeepCopy()
+	saved, isSaved := cj.Annotations[SuspendedCronJobAnnotationKey]
+
+	switch {
+	case suspended && !isSaved:
+		if cj.Annotations == nil {
+			cj.Annotations = map[string]string{}
+		}
+		cj.Annotations[SuspendedCronJobAnnotationKey] = strconv.FormatBool(ptr.Deref(cj.Spec.Suspend, false))
+		cj.Spec.Suspend = ptr.To(true)
+
+	case suspended:
+		cj.Spec.Suspend = ptr.To(true)
+
+	case isSaved:
+		wasSuspended, _ := strconv.ParseBool(saved)
+		delete(cj.Annotations, SuspendedCronJobAnnotationKey)
+		cj.Spec.Suspend = ptr.To(wasSuspended)
+
+	default:
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(original, cj) {
+		return nil
+	}
+
+	if err := r.Patch(ctx, cj, client.MergeFrom(original)); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.Update, suspensionReason(suspended), original, cj)
+	return nil
+}
+
+// suspensionReason is the audit reason of a suspension change
+func suspensionReason(suspended bool) string {
+	if suspended {
+		return EventReasonSuspended
+	}
+	return EventReasonResumed
+}
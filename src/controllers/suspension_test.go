@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSuspendAndResumeWorkloads(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a"},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](3)},
+	}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](2)},
+	}
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "team-a"},
+		Spec:       batchv1.CronJobSpec{Schedule: "@daily"},
+	}
+	pausedCronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "paused", Namespace: "team-a"},
+		Spec:       batchv1.CronJobSpec{Schedule: "@daily", Suspend: ptr.To(true)},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(deployment, statefulSet, cronJob, pausedCronJob).
+		Build()
+
+	r := &TenantReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec:       platformv1alpha1.TenantSpec{Namespace: "team-a"},
+	}
+
+	get := func(obj client.Object) {
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+	}
+
+	// -------------------------------------------------------------------------
+	// Suspend (twice, to check the saved values are not overwritten)
+	// -------------------------------------------------------------------------
+	g.Expect(r.reconcileSuspension(ctx, tenant, true)).To(Succeed())
+	g.Expect(r.reconcileSuspension(ctx, tenant, true)).To(Succeed())
+
+	// Found from the workloads, whatever the tenant status says
+	inNamespace := client.InNamespace(tenant.Spec.Namespace)
+	found, err := r.hasSuspendedWorkloads(ctx, inNamespace)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+
+	get(deployment)
+	g.Expect(*deployment.Spec.Replicas).To(BeZero())
+	g.Expect(deployment.Annotations).To(HaveKeyWithValue(SuspendedReplicasAnnotationKey, "3"))
+
+	get(statefulSet)
+	g.Expect(*statefulSet.Spec.Replicas).To(BeZero())
+
+	get(cronJob)
+	g.Expect(*cronJob.Spec.Suspend).To(BeTrue())
+
+	// -------------------------------------------------------------------------
+	// Resume
+	// -------------------------------------------------------------------------
+	g.Expect(r.reconcileSuspension(ctx, tenant, false)).To(Succeed())
+
+	get(deployment)
+	g.Expect(*deployment.Spec.Replicas).To(Equal(int32(3)))
+	g.Expect(deployment.Annotations).NotTo(HaveKey(SuspendedReplicasAnnotationKey))
+
+	get(statefulSet)
+	g.Expect(*statefulSet.Spec.Replicas).To(Equal(int32(2)))
+
+	get(cronJob)
+	g.Expect(*cronJob.Spec.Suspend).To(BeFalse())
+
+	get(pausedCronJob)
+	g.Expect(*pausedCronJob.Spec.Suspend).To(BeTrue())
+
+	found, err = r.hasSuspendedWorkloads(ctx, inNamespace)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"strings"
+	"text/template"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// templateData is the data available to templates rendered per tenant, e.g.
+// {{ .Tenant.Name }}, {{ .Namespace }} or {{ .Profile }}.
+// -----------------------------------------------------------------------------
+type templateData struct {
+	Tenant    *platformv1alpha1.Tenant
+	Namespace string
+	Profile   string
+}
+
+func newTemplateData(
+	tenant *platformv1alpha1.Tenant,
+	profile *platformv1alpha1.TenantProfile,
+) templateData {
+	data := templateData{
+		Tenant:    tenant,
+		Namespace: tenant.Spec.Namespace,
+	}
+	if profile != nil {
+		data.Profile = profile.Name
+	}
+	return data
+}
+
+// -----------------------------------------------------------------------------
+// renderTemplate executes a Go template. Missing keys are an error rather
+// than rendering "<no value>" into the object.
+// -----------------------------------------------------------------------------
+func renderTemplate(name, text string, data templateData) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
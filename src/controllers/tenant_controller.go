@@ -4,37 +4,94 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/audit"
+	"github.com/tngs/namespace-operator/config"
+	"github.com/tngs/namespace-operator/features"
+	"github.com/tngs/namespace-operator/tracing"
+
+	"go.opentelemetry.io/otel/trace"
 
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-const tenantFinalizer = "platform.example.com/finalizer"
+const (
+	tenantFinalizer = "platform.example.com/finalizer"
+
+	// tenantProfileIndex indexes Tenants by the profile they reference
+	tenantProfileIndex = "spec.profile"
+)
 
 // -----------------------------------------------------------------------------
 // RBAC
 // -----------------------------------------------------------------------------
 
-// +kubebuilder:rbac:groups=platform.example.com,resources=tenants,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=platform.example.com,resources=tenants,verbs=get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups=platform.example.com,resources=tenants/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;delete
-// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch;create;update;patch
-// +kubebuilder:rbac:groups="",resources=limitranges,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=platform.example.com,resources=tenants/finalizers,verbs=update
+// +kubebuilder:rbac:groups=platform.example.com,resources=tenantprofiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups="",resources=limitranges,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind,resourceNames=admin;edit;view
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;patch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;patch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=get;list;watch
 
 type TenantReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// Config is the operator configuration, the defaults when nil
+	Config *config.Store
+
+	// Features are the feature gates, the defaults when nil
+	Features *features.Gates
+
+	// APIReader reads the objects the cache does not hold (bootstrap sources,
+	// ServiceAccounts); the cached client when nil
+	APIReader client.Reader
+
+	// Audit receives the changes made to the cluster, nil to disable
+	Audit audit.Sink
+}
+
+// -----------------------------------------------------------------------------
+// tenantConfig is the effective configuration of a Tenant once its profile
+// (if any) has been resolved.
+// -----------------------------------------------------------------------------
+type tenantConfig struct {
+	// Profile is nil when the tenant is configured inline
+	Profile *platformv1alpha1.TenantProfile
+
+	Quota  *platformv1alpha1.QuotaSpec
+	Limits *platformv1alpha1.LimitSpec
 }
 
 // ---------------------------------------------------------------------------------------------------
@@ -46,35 +103,45 @@ type TenantReconciler struct {
 func (r *TenantReconciler) resolveConfig(
 	ctx context.Context,
 	tenant *platformv1alpha1.Tenant,
-) (*platformv1alpha1.QuotaSpec, *platformv1alpha1.LimitSpec, error) {
+) (cfg *tenantConfig, err error) {
+
+	ctx, span := tracing.Start(ctx, "ResolveConfig")
+	defer func() { tracing.End(span, err) }()
 
 	// If a profile is specified, it takes precedence over direct quota/limit settings
 	if tenant.Spec.Profile != nil {
+		span.SetAttributes(tracing.ProfileKey.String(*tenant.Spec.Profile))
+
 		// Fetch the profile to get the quota and limits
 		profile := &platformv1alpha1.TenantProfile{}
 		if err := r.Get(ctx, client.ObjectKey{
 			Name: *tenant.Spec.Profile,
 		}, profile); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
-		return &profile.Spec.Quota, &profile.Spec.Limits, nil
+		return &tenantConfig{
+			Profile: profile,
+			Quota:   &profile.Spec.Quota,
+			Limits:  &profile.Spec.Limits,
+		}, nil
 	}
 
 	// If no profile is specified, both quota and limits must be set directly on the tenant
 	if tenant.Spec.Quota != nil && tenant.Spec.Limits != nil {
-		return tenant.Spec.Quota, tenant.Spec.Limits, nil
+		return &tenantConfig{
+			Quota:  tenant.Spec.Quota,
+			Limits: tenant.Spec.Limits,
+		}, nil
 	}
 
-	return nil, nil, fmt.Errorf(
+	return nil, fmt.Errorf(
 		"either spec.profile or spec.quota + spec.limits must be set",
 	)
 }
 
 // -----------------------------------------------------------------------------
-// Reconcile
-// The Reconcile function is the heart of the controller.
-// It is called whenever a Tenant resource is created, updated, or deleted.
+// Reconcile records the duration, outcome and trace of each Tenant reconcile.
 // -----------------------------------------------------------------------------
 func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 
@@ -83,21 +150,59 @@ func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		TenantReconcileDuration.Observe(time.Since(start).Seconds())
 	}()
 
+	ctx, span := tracing.Start(ctx, "Tenant.Reconcile", tracing.TenantKey.String(req.Name))
+	ctx = audit.NewContext(ctx, r.Audit, "tenant")
+
+	result, err := r.reconcile(ctx, req)
+	observeReconcile("tenant", result, err)
+	tracing.End(span, err)
+
+	return result, err
+}
+
+// -----------------------------------------------------------------------------
+// reconcile
+// The reconcile function is the heart of the controller.
+// It is called whenever a Tenant resource is created, updated, or deleted.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+
 	logger := log.FromContext(ctx)
 
 	var tenant platformv1alpha1.Tenant
 	if err := r.Get(ctx, req.NamespacedName, &tenant); err != nil {
+		if apierrors.IsNotFound(err) {
+			forgetTenantMetrics(req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	ctx = audit.WithTenant(ctx, tenant.Name, tenant.Generation)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(tracing.NamespaceKey.String(tenant.Spec.Namespace))
+	if tenant.Spec.Profile != nil {
+		span.SetAttributes(tracing.ProfileKey.String(*tenant.Spec.Profile))
+	}
+
+	// Operator configuration, read once so a reload applies to the next reconcile
+	opCfg := r.Config.Get()
+
+	// Tenants of other shards are reconciled by other operator instances
+	if !opCfg.Sharding.Owns(tenant.Name, tenant.Labels) {
+		forgetTenantMetrics(tenant.Name)
+		return ctrl.Result{}, nil
+	}
+
 	// -------------------------------------------------------------------------
 	// Deletion handling (finalizer)
 	// -------------------------------------------------------------------------
+	// The namespace is deleted or released according to the deletion policy.
 	if !tenant.DeletionTimestamp.IsZero() {
 
-		ns := &corev1.Namespace{}
-		if err := r.Get(ctx, client.ObjectKey{Name: tenant.Spec.Namespace}, ns); err == nil {
-			_ = r.Delete(ctx, ns)
+		if err := r.cleanupNamespace(ctx, &tenant, opCfg); err != nil {
+			TenantReconcileErrors.Inc()
+			return ctrl.Result{}, err
 		}
 
 		controllerutil.RemoveFinalizer(&tenant, tenantFinalizer)
@@ -105,6 +210,7 @@ func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			TenantReconcileErrors.Inc()
 			return ctrl.Result{}, err
 		}
+		forgetTenantMetrics(tenant.Name)
 
 		return ctrl.Result{}, nil
 	}
@@ -125,39 +231,172 @@ func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 	}
 
+	// -------------------------------------------------------------------------
+	// Expiration
+	// -------------------------------------------------------------------------
+	// An expired tenant is deleted, the finalizer then cleans up its namespace.
+	// An invalid extend-until annotation is reported and ignored. With the
+	// Expiration gate off, tenants never expire.
+	var expiresAt *time.Time
+	if r.Features.Enabled(features.Expiration) {
+		var err error
+		expiresAt, err = tenantExpiry(&tenant)
+		if err != nil {
+			events(r.Recorder).Event(&tenant, corev1.EventTypeWarning, EventReasonInvalidExpiry, err.Error())
+		}
+	}
+	if expiresAt != nil && !time.Now().Before(*expiresAt) {
+		events(r.Recorder).Eventf(
+			&tenant,
+			corev1.EventTypeNormal,
+			EventReasonExpired,
+			"Tenant expired at %s, deleting it",
+			expiresAt.Format(time.RFC3339),
+		)
+		if err := r.Delete(ctx, &tenant); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				return ctrl.Result{}, nil
+			}
+			TenantReconcileErrors.Inc()
+			return ctrl.Result{}, err
+		}
+		audit.Record(ctx, audit.Delete, EventReasonExpired, &tenant, nil)
+		TenantsExpired.Inc()
+		logger.Info("deleted expired tenant", "expiresAt", expiresAt)
+		return ctrl.Result{}, nil
+	}
+
+	// -------------------------------------------------------------------------
+	// Reserved namespaces
+	// -------------------------------------------------------------------------
+	// Not retried: the Tenant watch brings the tenant back once it is fixed.
+	if opCfg.IsReserved(tenant.Spec.Namespace) {
+		events(r.Recorder).Eventf(
+			&tenant,
+			corev1.EventTypeWarning,
+			EventReasonReservedNamespace,
+			"Namespace %q is reserved and cannot be managed by a Tenant",
+			tenant.Spec.Namespace,
+		)
+		TenantReady.WithLabelValues(tenant.Name).Set(0)
+		return ctrl.Result{}, reconcile.TerminalError(
+			fmt.Errorf("namespace %q is reserved", tenant.Spec.Namespace),
+		)
+	}
+
 	// -------------------------------------------------------------------------
 	// Resolve configuration
-	// -------------------------------------------------------------------------e.
-	quota, limits, err := r.resolveConfig(ctx, &tenant)
+	// -------------------------------------------------------------------------
+	// A missing profile or inline configuration is not retried: the Tenant or
+	// TenantProfile watch brings the tenant back once it is fixed.
+	cfg, err := r.resolveConfig(ctx, &tenant)
 	if err != nil {
-		logger.Error(err, "invalid tenant configuration")
 		TenantReconcileErrors.Inc()
-		return ctrl.Result{}, nil
+		TenantReady.WithLabelValues(tenant.Name).Set(0)
+		return ctrl.Result{}, reconcile.TerminalError(fmt.Errorf("invalid tenant configuration: %w", err))
 	}
 
+	quota, limits := cfg.Quota, cfg.Limits
 	nsName := tenant.Spec.Namespace
+	applied := tenantApplied(&tenant, cfg.Profile)
 
 	// -------------------------------------------------------------------------
 	// Namespace
 	// -------------------------------------------------------------------------
+	// The namespace is server-side applied: the operator only owns the labels
+	// and owner reference it sets, so other tools can add their own labels and
+	// annotations without being clobbered.
+	nsLabels, nsAnnotations, reserved := namespaceMetadata(&tenant, cfg.Profile)
+	if len(reserved) > 0 {
+		events(r.Recorder).Eventf(
+			&tenant,
+			corev1.EventTypeWarning,
+			EventReasonReservedMetadata,
+			"Ignoring reserved namespace metadata keys: %s",
+			strings.Join(reserved, ", "),
+		)
+	}
+
+	psLabels, clamped := podSecurityLabels(&tenant, cfg.Profile, opCfg.PodSecurity.MinimumEnforce)
+	if clamped {
+		events(r.Recorder).Eventf(
+			&tenant,
+			corev1.EventTypeWarning,
+			EventReasonPodSecurityClamped,
+			"Pod Security enforce level raised to the minimum %q",
+			psLabels[podSecurityEnforceLabel],
+		)
+	}
+	for k, v := range psLabels {
+		nsLabels[k] = v
+	}
+
+	placement, err := placementAnnotations(cfg.Profile)
+	if err != nil {
+		TenantReconcileErrors.Inc()
+		return ctrl.Result{}, err
+	}
+	for k, v := range placement {
+		nsAnnotations[k] = v
+	}
+
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: nsName,
-			Labels: map[string]string{
-				ManagedByLabelKey: ManagedByLabelValue,
-			},
+			Name:        nsName,
+			Labels:      nsLabels,
+			Annotations: nsAnnotations,
 		},
 	}
+	ns.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+
+	// Set the tenant as the owner of the namespace it creates, so it gets
+	// deleted with the tenant; a namespace that existed before the tenant is
+	// adopted without owner reference, and released when the tenant is deleted
+	adopted, err := r.namespaceAdopted(ctx, &tenant)
+	if err != nil {
+		TenantReconcileErrors.Inc()
+		return ctrl.Result{}, err
+	}
+	if !adopted {
+		if err := controllerutil.SetControllerReference(&tenant, ns, r.Scheme); err != nil {
+			TenantReconcileErrors.Inc()
+			return ctrl.Result{}, err
+		}
+	}
 
-	// Set the tenant as the owner of the namespace, so it gets deleted automatically when the tenant is deleted
-	if err := controllerutil.SetControllerReference(&tenant, ns, r.Scheme); err != nil {
+	if _, err := applyObject(ctx, r.Client, ns); err != nil {
 		TenantReconcileErrors.Inc()
 		return ctrl.Result{}, err
 	}
 
-	// Create the namespace if it doesn't exist. If it already exists, that's fine -
-	// we just want to ensure it exists and is owned by the tenant.
-	if err := r.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+	// -------------------------------------------------------------------------
+	// Hibernation schedule
+	// -------------------------------------------------------------------------
+	// An invalid schedule is reported and ignored (the tenant stays awake);
+	// the webhook rejects it up front when enabled. With the Hibernation gate
+	// off, the schedule is ignored.
+	var result ctrl.Result
+
+	var schedule scheduleState
+	if r.Features.Enabled(features.Hibernation) {
+		schedule, err = evaluateSchedule(&tenant, time.Now())
+		if err != nil {
+			events(r.Recorder).Event(&tenant, corev1.EventTypeWarning, EventReasonInvalidSchedule, err.Error())
+		} else if tenant.Spec.Schedule != nil {
+			result.RequeueAfter = time.Until(schedule.Next)
+		}
+	}
+
+	// Come back when the expiration warning is due, then when expired
+	if expiresAt != nil {
+		requeueBefore(&result, expiryRequeue(*expiresAt, time.Now(), opCfg.ExpiryWarning.Duration))
+	}
+
+	// -------------------------------------------------------------------------
+	// Allowed StorageClasses and PriorityClasses
+	// -------------------------------------------------------------------------
+	storageClasses, priorityClasses, err := r.classRestrictions(ctx, cfg.Profile)
+	if err != nil {
 		TenantReconcileErrors.Inc()
 		return ctrl.Result{}, err
 	}
@@ -167,65 +406,137 @@ func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	// -------------------------------------------------------------------------
 	rq := &corev1.ResourceQuota{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "tenant-quota",
+			Name:      ResourceQuotaName,
 			Namespace: nsName,
 		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(quota.CPU),
+				corev1.ResourceMemory: resource.MustParse(quota.Memory),
+				corev1.ResourcePods:   resource.MustParse(strconv.Itoa(int(quota.Pods))),
+			},
+		},
+	}
+	// Disallowed StorageClasses get a zero quota
+	for name, q := range storageClassQuota(storageClasses.Denied) {
+		rq.Spec.Hard[name] = q
 	}
 
-	// Create or update the ResourceQuota with the specified limits.
+	// No new pods while the tenant is suspended or hibernating
+	suspended := tenant.Spec.Suspended || schedule.Asleep
+	if suspended {
+		rq.Spec.Hard[corev1.ResourcePods] = resource.MustParse("0")
+	}
+	rq.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ResourceQuota"))
+	rq.Labels = objectLabels(&tenant, rq.Spec)
+
+	if err := controllerutil.SetControllerReference(&tenant, rq, r.Scheme); err != nil {
+		TenantReconcileErrors.Inc()
+		return ctrl.Result{}, err
+	}
+
+	// Apply the ResourceQuota with the specified limits.
 	// If it doesn't exist, it will be created. If it already exists,
 	// it will be updated with the new limits.
-	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, rq, func() error {
-		rq.Spec.Hard = corev1.ResourceList{
-			corev1.ResourceCPU:    resource.MustParse(quota.CPU),
-			corev1.ResourceMemory: resource.MustParse(quota.Memory),
-			corev1.ResourcePods:   resource.MustParse(strconv.Itoa(int(quota.Pods))),
-		}
-		return nil
-	}); err != nil {
+	res, err := applyObject(ctx, r.Client, rq)
+	if err != nil {
 		TenantReconcileErrors.Inc()
 		return ctrl.Result{}, err
 	}
+	if res.drifted(rq.Labels[SpecHashLabelKey], applied) {
+		recordDrift(r.Recorder, &tenant, "ResourceQuota", nsName, rq.Name)
+	}
 
 	// -------------------------------------------------------------------------
 	// LimitRange
 	// -------------------------------------------------------------------------
 	lr := &corev1.LimitRange{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "tenant-limits",
+			Name:      LimitRangeName,
 			Namespace: nsName,
 		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{
+				{
+					Type: corev1.LimitTypeContainer,
+					Default: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(limits.DefaultCPU),
+						corev1.ResourceMemory: resource.MustParse(limits.DefaultMemory),
+					},
+					Max: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(limits.MaxCPU),
+						corev1.ResourceMemory: resource.MustParse(limits.MaxMemory),
+					},
+				},
+			},
+		},
+	}
+	lr.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("LimitRange"))
+	lr.Labels = objectLabels(&tenant, lr.Spec)
+
+	if err := controllerutil.SetControllerReference(&tenant, lr, r.Scheme); err != nil {
+		TenantReconcileErrors.Inc()
+		return ctrl.Result{}, err
 	}
 
-	// Create or update the LimitRange with the specified limits.
+	// Apply the LimitRange with the specified limits.
 	// If it doesn't exist, it will be created. If it already exists,
 	// it will be updated with the new limits.
-	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, lr, func() error {
-		lr.Spec.Limits = []corev1.LimitRangeItem{
-			{
-				Type: corev1.LimitTypeContainer,
-				Default: corev1.ResourceList{
-					corev1.ResourceCPU:    resource.MustParse(limits.DefaultCPU),
-					corev1.ResourceMemory: resource.MustParse(limits.DefaultMemory),
-				},
-				Max: corev1.ResourceList{
-					corev1.ResourceCPU:    resource.MustParse(limits.MaxCPU),
-					corev1.ResourceMemory: resource.MustParse(limits.MaxMemory),
-				},
-			},
-		}
-		return nil
-	}); err != nil {
+	res, err = applyObject(ctx, r.Client, lr)
+	if err != nil {
+		TenantReconcileErrors.Inc()
+		return ctrl.Result{}, err
+	}
+	if res.drifted(lr.Labels[SpecHashLabelKey], applied) {
+		recordDrift(r.Recorder, &tenant, "LimitRange", nsName, lr.Name)
+	}
+
+	// -------------------------------------------------------------------------
+	// PriorityClass quota
+	// -------------------------------------------------------------------------
+	if err := r.reconcilePriorityClassQuota(ctx, &tenant, priorityClasses.Denied, applied); err != nil {
+		TenantReconcileErrors.Inc()
+		return ctrl.Result{}, err
+	}
+
+	// -------------------------------------------------------------------------
+	// Suspension (workloads scaled to zero, CronJobs suspended)
+	// -------------------------------------------------------------------------
+	// Resumed from the workload annotations, not from status.suspendedSince:
+	// the workloads may have been scaled down by a reconcile that failed
+	// before patching the status
+	if err := r.reconcileSuspension(ctx, &tenant, suspended); err != nil {
 		TenantReconcileErrors.Inc()
+		logger.Error(err, "unable to reconcile tenant suspension")
 		return ctrl.Result{}, err
 	}
 
 	// -------------------------------------------------------------------------
-	// Update metrics (total tenants)
+	// Access (RoleBindings)
 	// -------------------------------------------------------------------------
-	var tenantList platformv1alpha1.TenantList
-	if err := r.List(ctx, &tenantList); err == nil {
-		TenantTotal.Set(float64(len(tenantList.Items)))
+	roleBindings, err := r.reconcileAccess(ctx, &tenant, cfg.Profile, opCfg.Access.AllowedRoles, applied)
+	if err != nil {
+		TenantReconcileErrors.Inc()
+		return ctrl.Result{}, err
+	}
+
+	// -------------------------------------------------------------------------
+	// Bootstrap objects (pull secrets, CA bundles, ...)
+	// -------------------------------------------------------------------------
+	if err := r.reconcileBootstrap(ctx, &tenant, cfg.Profile, applied); err != nil {
+		TenantReconcileErrors.Inc()
+		logger.Error(err, "unable to reconcile bootstrap objects")
+		return ctrl.Result{}, err
+	}
+
+	// -------------------------------------------------------------------------
+	// Resource templates
+	// -------------------------------------------------------------------------
+	resources, err := r.reconcileResources(ctx, &tenant, cfg.Profile, applied)
+	if err != nil {
+		TenantReconcileErrors.Inc()
+		logger.Error(err, "unable to reconcile resource templates")
+		return ctrl.Result{}, err
 	}
 
 	// -------------------------------------------------------------------------
@@ -233,12 +544,84 @@ func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	// -------------------------------------------------------------------------
 	original := tenant.DeepCopy()
 
+	tenant.Status.ObservedGeneration = tenant.Generation
+	tenant.Status.ObservedProfileGeneration = 0
+	if cfg.Profile != nil {
+		tenant.Status.ObservedProfileGeneration = cfg.Profile.Generation
+	}
+	tenant.Status.RoleBindings = roleBindings
+	tenant.Status.Resources = resources
+	tenant.Status.AllowedStorageClasses = storageClasses.Allowed
+	tenant.Status.AllowedPriorityClasses = priorityClasses.Allowed
+
+	switch {
+	case suspended && tenant.Status.SuspendedSince == nil:
+		now := metav1.Now()
+		tenant.Status.SuspendedSince = &now
+		events(r.Recorder).Event(&tenant, corev1.EventTypeNormal, EventReasonSuspended, "Workloads scaled to zero")
+	case !suspended && tenant.Status.SuspendedSince != nil:
+		tenant.Status.SuspendedSince = nil
+		events(r.Recorder).Event(&tenant, corev1.EventTypeNormal, EventReasonResumed, "Workloads restored")
+	}
+
+	switch {
+	case tenant.Spec.Suspended:
+		setCondition(
+			&tenant.Status.Conditions,
+			"Suspended",
+			metav1.ConditionTrue,
+			"Suspended",
+			"Workloads scaled to zero and new pods blocked",
+		)
+	case schedule.Asleep:
+		setCondition(
+			&tenant.Status.Conditions,
+			"Suspended",
+			metav1.ConditionTrue,
+			"Hibernating",
+			fmt.Sprintf("Hibernating until %s", schedule.Next.Format(time.RFC3339)),
+		)
+	case schedule.WokenUntil != nil:
+		setCondition(
+			&tenant.Status.Conditions,
+			"Suspended",
+			metav1.ConditionFalse,
+			"WokenUp",
+			fmt.Sprintf("Schedule overridden until %s", schedule.WokenUntil.Format(time.RFC3339)),
+		)
+	default:
+		setCondition(
+			&tenant.Status.Conditions,
+			"Suspended",
+			metav1.ConditionFalse,
+			"Active",
+			"Tenant is active",
+		)
+	}
+
+	if expiresAt == nil {
+		tenant.Status.ExpiresAt = nil
+		meta.RemoveStatusCondition(&tenant.Status.Conditions, "Expiring")
+	} else {
+		tenant.Status.ExpiresAt = &metav1.Time{Time: *expiresAt}
+		message := fmt.Sprintf("Tenant expires at %s", expiresAt.Format(time.RFC3339))
+
+		if time.Until(*expiresAt) <= opCfg.ExpiryWarning.Duration {
+			if !meta.IsStatusConditionTrue(original.Status.Conditions, "Expiring") {
+				events(r.Recorder).Event(&tenant, corev1.EventTypeWarning, EventReasonExpiringSoon, message)
+			}
+			setCondition(&tenant.Status.Conditions, "Expiring", metav1.ConditionTrue, "ExpiringSoon", message)
+		} else {
+			setCondition(&tenant.Status.Conditions, "Expiring", metav1.ConditionFalse, "Scheduled", message)
+		}
+	}
+
 	setCondition(
 		&tenant.Status.Conditions,
 		"Ready",
 		metav1.ConditionTrue,
 		"Reconciled",
-		"Namespace, quota and limits applied",
+		"Namespace, quota, limits, access, bootstrap and template objects applied",
 	)
 
 	// Patch the status subresource to update the status of the tenant.
@@ -248,18 +631,199 @@ func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		logger.Error(err, "unable to patch Tenant status")
 		return ctrl.Result{}, err
 	}
+	TenantReady.WithLabelValues(tenant.Name).Set(1)
 
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// ownsTenant reports whether a Tenant, read from reader, belongs to this shard
+func (r *TenantReconciler) ownsTenant(reader client.Reader) func(string) bool {
+	sharding := r.Config.Get().Sharding
+	return func(name string) bool {
+		if !sharding.Enabled() {
+			return true
+		}
+		tenant := &platformv1alpha1.Tenant{}
+		if err := reader.Get(context.Background(), client.ObjectKey{Name: name}, tenant); err != nil {
+			return false
+		}
+		return sharding.Owns(name, tenant.Labels)
+	}
+}
+
+// reader returns the client reading around the cache
+func (r *TenantReconciler) reader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
 }
 
 // -----------------------------------------------------------------------------
 // The SetupWithManager function sets up the controller with the Manager.
 // It tells the controller to watch for Tenant resources and also to watch for
 // Namespaces that are owned by Tenants, so it can react to changes in those as well.
+// Managed ResourceQuotas and LimitRanges are watched too, so manual edits or
+// deletions are corrected immediately instead of at the next resync, and
+// TenantProfile changes are propagated to every Tenant using the profile.
+// Sources of bootstrap copies in bootstrap.sourceNamespaces are watched so the
+// copies stay in sync, and
+// StorageClasses/PriorityClasses so new classes are blocked when not allowed.
 // -----------------------------------------------------------------------------
 func (r *TenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
+
+	// Index Tenant by spec.profile
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&platformv1alpha1.Tenant{},
+		tenantProfileIndex,
+		func(obj client.Object) []string {
+			tenant := obj.(*platformv1alpha1.Tenant)
+			if tenant.Spec.Profile == nil {
+				return nil
+			}
+			return []string{*tenant.Spec.Profile}
+		},
+	); err != nil {
+		return err
+	}
+
+	// Index TenantProfile by the objects its bootstrap section copies
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&platformv1alpha1.TenantProfile{},
+		profileBootstrapSourceIndex,
+		func(obj client.Object) []string {
+			profile := obj.(*platformv1alpha1.TenantProfile)
+			if profile.Spec.Bootstrap == nil {
+				return nil
+			}
+			keys := make([]string, 0, len(profile.Spec.Bootstrap.Copy))
+			for _, c := range profile.Spec.Bootstrap.Copy {
+				keys = append(keys, bootstrapSourceKey(c.Kind, c.SourceNamespace, c.Name))
+			}
+			return keys
+		},
+	); err != nil {
+		return err
+	}
+
+	// Tenant counts follow the informer instead of listing on every reconcile
+	informer, err := mgr.GetCache().GetInformer(context.Background(), &platformv1alpha1.Tenant{})
+	if err != nil {
+		return err
+	}
+	if _, err := informer.AddEventHandler(newTenantCounter().handler()); err != nil {
+		return err
+	}
+
+	// Quota usage follows the informer, quota status updates are filtered out
+	quotas, err := mgr.GetCache().GetInformer(context.Background(), &corev1.ResourceQuota{})
+	if err != nil {
+		return err
+	}
+	if _, err := quotas.AddEventHandler(quotaObserver{owns: r.ownsTenant(mgr.GetCache())}.handler()); err != nil {
+		return err
+	}
+
+	sourceNamespaces := r.Config.Get().Bootstrap.SourceNamespaces
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&platformv1alpha1.Tenant{}).
-		Owns(&corev1.Namespace{}).
+		For(&platformv1alpha1.Tenant{}, builder.WithPredicates(tenantShardPredicate(r.Config.Get().Sharding))).
+		WithOptions(controllerOptions(r.Config.Get().Controllers.Tenant)).
+		Watches(
+			&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(tenantForNamespace),
+			builder.WithPredicates(managedByPredicate, namespaceChangedPredicate),
+		).
+		Owns(&corev1.ResourceQuota{}, builder.WithPredicates(managedByPredicate, quotaChangedPredicate)).
+		Owns(&corev1.LimitRange{}, builder.WithPredicates(managedByPredicate)).
+		Owns(&rbacv1.RoleBinding{}, builder.WithPredicates(managedByPredicate)).
+		Owns(&corev1.Secret{}, builder.WithPredicates(managedByPredicate)).
+		Owns(&corev1.ConfigMap{}, builder.WithPredicates(managedByPredicate)).
+		Watches(
+			&platformv1alpha1.TenantProfile{},
+			handler.EnqueueRequestsFromMapFunc(r.tenantsForProfile),
+		).
+		Watches(
+			&storagev1.StorageClass{},
+			handler.EnqueueRequestsFromMapFunc(r.tenantsForClassChange),
+			builder.WithPredicates(classLifecyclePredicate),
+		).
+		Watches(
+			&schedulingv1.PriorityClass{},
+			handler.EnqueueRequestsFromMapFunc(r.tenantsForClassChange),
+			builder.WithPredicates(classLifecyclePredicate),
+		).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.tenantsForBootstrapSource(platformv1alpha1.BootstrapKindSecret)),
+			builder.WithPredicates(inNamespaces(sourceNamespaces)),
+		).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.tenantsForBootstrapSource(platformv1alpha1.BootstrapKindConfigMap)),
+			builder.WithPredicates(inNamespaces(sourceNamespaces)),
+		).
 		Complete(r)
 }
+
+// -----------------------------------------------------------------------------
+// namespaceAdopted reports whether the tenant namespace existed before the
+// tenant: it exists without the tenant as controller owner. Namespaces not
+// managed yet are not cached, they are read from the API server.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) namespaceAdopted(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+) (bool, error) {
+
+	key := client.ObjectKey{Name: tenant.Spec.Namespace}
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, key, ns); err == nil && metav1.IsControlledBy(ns, tenant) {
+		return false, nil
+	}
+
+	if err := r.reader().Get(ctx, key, ns); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return !metav1.IsControlledBy(ns, tenant), nil
+}
+
+// tenantForNamespace maps a managed namespace, created or adopted, to its
+// Tenant
+func tenantForNamespace(_ context.Context, obj client.Object) []reconcile.Request {
+	name := obj.GetLabels()[TenantLabelKey]
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: name}}}
+}
+
+// -----------------------------------------------------------------------------
+// tenantsForProfile maps a TenantProfile to the Tenants referencing it.
+// -----------------------------------------------------------------------------
+func (r *TenantReconciler) tenantsForProfile(
+	ctx context.Context,
+	obj client.Object,
+) []reconcile.Request {
+
+	var tenants platformv1alpha1.TenantList
+	if err := r.List(
+		ctx,
+		&tenants,
+		client.MatchingFields{tenantProfileIndex: obj.GetName()},
+	); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Tenants for profile", "profile", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(tenants.Items))
+	for _, tenant := range tenants.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKey{Name: tenant.Name},
+		})
+	}
+
+	return requests
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
 	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
 
 	corev1 "k8s.io/api/core/v1"
@@ -12,8 +14,11 @@ import (
 
 	. "github.com/onsi/gomega"
 
+	"k8s.io/utils/ptr"
+
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
 )
 
 func TestTenantCreatesNamespace(t *testing.T) {
@@ -36,8 +41,9 @@ func TestTenantCreatesNamespace(t *testing.T) {
 	g.Expect(err).NotTo(HaveOccurred())
 
 	reconciler := &TenantReconciler{
-		Client: mgr.GetClient(), // 🔥 IMPORTANT
-		Scheme: mgr.GetScheme(), // 🔥 IMPORTANT
+		Client:   mgr.GetClient(), // 🔥 IMPORTANT
+		Scheme:   mgr.GetScheme(), // 🔥 IMPORTANT
+		Recorder: mgr.GetEventRecorderFor("namespace-operator"),
 	}
 
 	g.Expect(reconciler.SetupWithManager(mgr)).To(Succeed())
@@ -88,4 +94,107 @@ func TestTenantCreatesNamespace(t *testing.T) {
 			ns,
 		)
 	}, 10*time.Second, 500*time.Millisecond).Should(Succeed())
+
+	g.Expect(ns.Labels).To(HaveKeyWithValue(TenantLabelKey, "team-a"))
+
+	// Quota carries the tenant labels and points back to the Tenant
+	rq := &corev1.ResourceQuota{}
+	g.Eventually(func() error {
+		return k8sClient.Get(
+			context.Background(),
+			client.ObjectKey{Name: ResourceQuotaName, Namespace: "team-a"},
+			rq,
+		)
+	}, 10*time.Second, 500*time.Millisecond).Should(Succeed())
+
+	g.Expect(rq.Labels).To(HaveKeyWithValue(ManagedByLabelKey, ManagedByLabelValue))
+	g.Expect(rq.Labels).To(HaveKeyWithValue(TenantLabelKey, "team-a"))
+	g.Expect(rq.Labels).To(HaveKey(SpecHashLabelKey))
+	g.Expect(metav1.GetControllerOf(rq)).NotTo(BeNil())
+	g.Expect(metav1.GetControllerOf(rq).Name).To(Equal("team-a"))
+}
+
+func TestTenantRestoresDeletedQuota(t *testing.T) {
+	g := NewWithT(t)
+
+	k8sClient, err := client.New(cfg, client.Options{
+		Scheme: scheme,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme: scheme,
+		Controller: config.Controller{
+			SkipNameValidation: ptr.To(true),
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	reconciler := &TenantReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("namespace-operator"),
+	}
+	g.Expect(reconciler.SetupWithManager(mgr)).To(Succeed())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = mgr.Start(ctx)
+	}()
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "team-drift",
+		},
+		Spec: platformv1alpha1.TenantSpec{
+			Namespace: "team-drift",
+			Quota: &platformv1alpha1.QuotaSpec{
+				CPU:    "1",
+				Memory: "1Gi",
+				Pods:   5,
+			},
+			Limits: &platformv1alpha1.LimitSpec{
+				DefaultCPU:    "100m",
+				DefaultMemory: "128Mi",
+				MaxCPU:        "500m",
+				MaxMemory:     "512Mi",
+			},
+		},
+	}
+	g.Expect(k8sClient.Create(context.Background(), tenant)).To(Succeed())
+
+	// Wait until the tenant has been fully applied once
+	g.Eventually(func() bool {
+		current := &platformv1alpha1.Tenant{}
+		if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(tenant), current); err != nil {
+			return false
+		}
+		return current.Status.ObservedGeneration == current.Generation
+	}, 10*time.Second, 500*time.Millisecond).Should(BeTrue())
+
+	before := testutil.ToFloat64(DriftCorrected.WithLabelValues("ResourceQuota"))
+
+	// Delete the quota behind the operator's back
+	rq := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ResourceQuotaName,
+			Namespace: "team-drift",
+		},
+	}
+	g.Expect(k8sClient.Delete(context.Background(), rq)).To(Succeed())
+
+	// 🔥 Wait until the quota is restored
+	g.Eventually(func() error {
+		return k8sClient.Get(
+			context.Background(),
+			client.ObjectKeyFromObject(rq),
+			&corev1.ResourceQuota{},
+		)
+	}, 10*time.Second, 500*time.Millisecond).Should(Succeed())
+
+	g.Eventually(func() float64 {
+		return testutil.ToFloat64(DriftCorrected.WithLabelValues("ResourceQuota"))
+	}, 10*time.Second, 500*time.Millisecond).Should(BeNumerically(">", before))
 }
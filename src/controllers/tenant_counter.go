@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"sync"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+// -----------------------------------------------------------------------------
+// tenantCounter keeps TenantTotal and ProfileTenants up to date from Tenant
+// informer events, so reconciles do not have to list every Tenant.
+// -----------------------------------------------------------------------------
+type tenantCounter struct {
+	mu       sync.Mutex
+	total    int
+	profiles map[string]int
+}
+
+func newTenantCounter() *tenantCounter {
+	return &tenantCounter{profiles: map[string]int{}}
+}
+
+func (c *tenantCounter) handler() toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			c.count(obj, 1)
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			if tenantProfileName(oldObj) != tenantProfileName(newObj) {
+				c.count(oldObj, -1)
+				c.count(newObj, 1)
+			}
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			c.count(obj, -1)
+		},
+	}
+}
+
+// count adds delta to the total and to the profile of the tenant obj
+func (c *tenantCounter) count(obj any, delta int) {
+	if _, ok := obj.(*platformv1alpha1.Tenant); !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.total += delta
+	TenantTotal.Set(float64(c.total))
+
+	profile := tenantProfileName(obj)
+	if profile == "" {
+		return
+	}
+	c.profiles[profile] += delta
+	if n := c.profiles[profile]; n > 0 {
+		ProfileTenants.WithLabelValues(profile).Set(float64(n))
+	} else {
+		delete(c.profiles, profile)
+		ProfileTenants.DeleteLabelValues(profile)
+	}
+}
+
+// tenantProfileName returns the profile of a Tenant, empty when inline
+func tenantProfileName(obj any) string {
+	tenant, ok := obj.(*platformv1alpha1.Tenant)
+	if !ok || tenant.Spec.Profile == nil {
+		return ""
+	}
+	return *tenant.Spec.Profile
+}
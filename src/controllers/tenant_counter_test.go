@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"testing"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTenantCounter(t *testing.T) {
+	g := NewWithT(t)
+
+	tenant := func(name, profile string) *platformv1alpha1.Tenant {
+		obj := &platformv1alpha1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if profile != "" {
+			obj.Spec.Profile = &profile
+		}
+		return obj
+	}
+
+	ProfileTenants.Reset()
+	h := newTenantCounter().handler()
+
+	h.OnAdd(tenant("a", "small"), true)
+	h.OnAdd(tenant("b", "small"), true)
+	h.OnAdd(tenant("c", ""), true)
+	g.Expect(testutil.ToFloat64(TenantTotal)).To(Equal(3.0))
+	g.Expect(testutil.ToFloat64(ProfileTenants.WithLabelValues("small"))).To(Equal(2.0))
+
+	// Moving a tenant to another profile
+	h.OnUpdate(tenant("b", "small"), tenant("b", "large"))
+	g.Expect(testutil.ToFloat64(TenantTotal)).To(Equal(3.0))
+	g.Expect(testutil.ToFloat64(ProfileTenants.WithLabelValues("small"))).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(ProfileTenants.WithLabelValues("large"))).To(Equal(1.0))
+
+	// Deletions, including missed ones, drop unused profiles
+	h.OnDelete(tenant("a", "small"))
+	h.OnDelete(toolscache.DeletedFinalStateUnknown{Key: "b", Obj: tenant("b", "large")})
+	g.Expect(testutil.ToFloat64(TenantTotal)).To(Equal(1.0))
+	g.Expect(testutil.CollectAndCount(ProfileTenants)).To(Equal(0))
+}
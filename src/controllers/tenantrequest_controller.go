@@ -0,0 +1,361 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/audit"
+	"github.com/tngs/namespace-operator/config"
+	"github.com/tngs/namespace-operator/tracing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// autoApprover is recorded as approver when the profile auto-approves
+const autoApprover = "auto-approved"
+
+// -----------------------------------------------------------------------------
+// RBAC
+// -----------------------------------------------------------------------------
+
+// +kubebuilder:rbac:groups=platform.example.com,resources=tenantrequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.example.com,resources=tenantrequests/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=platform.example.com,resources=tenants,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=platform.example.com,resources=tenantprofiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// -----------------------------------------------------------------------------
+// TenantRequestReconciler turns approved TenantRequests into Tenants.
+// Requests are checked against the self-service settings of their profile
+// (allowed groups, per-group tenant cap, auto-approval).
+// -----------------------------------------------------------------------------
+type TenantRequestReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+
+	// Config is the operator configuration, the defaults when nil
+	Config *config.Store
+
+	// APIReader reads namespaces without the cache, which may only hold the
+	// managed ones, and counts Tenants without lagging behind the ones just
+	// created; the cached client when nil
+	APIReader client.Reader
+
+	// WebhookEnabled is set when the TenantRequest webhook is served. Only
+	// then are the requester and approval annotations set on behalf of the
+	// authenticated user; without it only auto-approved requests are
+	// provisioned and the requester is not recorded.
+	WebhookEnabled bool
+
+	// Audit receives the Tenants created for requests, nil to disable
+	Audit audit.Sink
+}
+
+func (r *TenantRequestReconciler) Reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+	ctx, span := tracing.Start(ctx, "TenantRequest.Reconcile",
+		tracing.NamespaceKey.String(req.Namespace),
+		tracing.NameKey.String(req.Name),
+	)
+
+	ctx = audit.NewContext(ctx, r.Audit, "tenantrequest")
+
+	result, err := r.reconcile(ctx, req)
+	observeReconcile("tenantrequest", result, err)
+	tracing.End(span, err)
+
+	return result, err
+}
+
+func (r *TenantRequestReconciler) reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+
+	logger := log.FromContext(ctx)
+
+	var treq platformv1alpha1.TenantRequest
+	if err := r.Get(ctx, req.NamespacedName, &treq); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// Provisioned requests are kept as a record only
+	if treq.Status.Phase == platformv1alpha1.TenantRequestProvisioned {
+		return ctrl.Result{}, nil
+	}
+
+	// The requester is recorded once in the status, which users cannot write
+	original := treq.DeepCopy()
+	if r.WebhookEnabled && treq.Status.Requester == "" {
+		treq.Status.Requester = treq.Annotations[platformv1alpha1.TenantRequestRequesterAnnotation]
+	}
+
+	phase, message, err := r.evaluate(ctx, &treq)
+	if err != nil {
+		logger.Error(err, "unable to evaluate TenantRequest")
+		return ctrl.Result{}, err
+	}
+
+	if phase == platformv1alpha1.TenantRequestProvisioned {
+		err := r.createTenant(ctx, &treq)
+		switch {
+		case err == nil:
+			treq.Status.Tenant = treq.Spec.Namespace
+
+		// Rejected by validation or by the Tenant webhook: retrying would
+		// not help
+		case apierrors.IsInvalid(err) || apierrors.IsForbidden(err):
+			phase = platformv1alpha1.TenantRequestDenied
+			message = fmt.Sprintf("tenant rejected: %v", err)
+			treq.Status.Approver = original.Status.Approver
+
+		default:
+			logger.Error(err, "unable to create Tenant for request")
+			return ctrl.Result{}, err
+		}
+	}
+
+	treq.Status.Phase = phase
+	treq.Status.Message = message
+
+	if treq.Status != original.Status {
+		switch phase {
+		case platformv1alpha1.TenantRequestProvisioned:
+			events(r.Recorder).Eventf(&treq, corev1.EventTypeNormal, phase, "Tenant %s created", treq.Status.Tenant)
+		case platformv1alpha1.TenantRequestDenied:
+			events(r.Recorder).Event(&treq, corev1.EventTypeWarning, phase, message)
+		}
+
+		if err := r.Status().Patch(ctx, &treq, client.MergeFrom(original)); err != nil {
+			logger.Error(err, "unable to patch TenantRequest status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// -----------------------------------------------------------------------------
+// evaluate validates the request against its profile and decides the phase.
+// It returns Provisioned once the request is valid and approved, and records
+// the approver on the request status.
+// -----------------------------------------------------------------------------
+func (r *TenantRequestReconciler) evaluate(
+	ctx context.Context,
+	treq *platformv1alpha1.TenantRequest,
+) (string, string, error) {
+
+	denied := func(format string, args ...any) (string, string, error) {
+		return platformv1alpha1.TenantRequestDenied, fmt.Sprintf(format, args...), nil
+	}
+
+	// -------------------------------------------------------------------------
+	// Profile must allow self-service for the requesting group
+	// -------------------------------------------------------------------------
+	profile := &platformv1alpha1.TenantProfile{}
+	if err := r.Get(ctx, client.ObjectKey{Name: treq.Spec.Profile}, profile); err != nil {
+		if apierrors.IsNotFound(err) {
+			return denied("profile %q not found", treq.Spec.Profile)
+		}
+		return "", "", err
+	}
+
+	selfService := profile.Spec.SelfService
+	if selfService == nil || !selfService.Enabled {
+		return denied("profile %q does not allow self-service", profile.Name)
+	}
+
+	// Only the webhook checks that the requester is a member of the group:
+	// without it, any group could be named to pass the group restrictions
+	if !r.WebhookEnabled && (len(selfService.AllowedGroups) > 0 || selfService.MaxTenantsPerGroup > 0) {
+		return platformv1alpha1.TenantRequestPending,
+			fmt.Sprintf("profile %q restricts groups, which requires the TenantRequest webhook", profile.Name), nil
+	}
+
+	if len(selfService.AllowedGroups) > 0 &&
+		!slices.Contains(selfService.AllowedGroups, treq.Spec.Group) {
+		return denied("group %q may not use profile %q", treq.Spec.Group, profile.Name)
+	}
+
+	// -------------------------------------------------------------------------
+	// Additional access only grants the roles the profile allows, and only to
+	// ServiceAccounts of the tenant namespace
+	// -------------------------------------------------------------------------
+	for _, b := range treq.Spec.Access {
+		if !slices.Contains(selfService.AllowedRoles, b.Role) {
+			return denied("profile %q does not allow granting role %q", profile.Name, b.Role)
+		}
+		for _, sa := range b.ServiceAccounts {
+			if sa.Namespace != "" && sa.Namespace != treq.Spec.Namespace {
+				return denied("ServiceAccount %s/%s is outside the tenant namespace", sa.Namespace, sa.Name)
+			}
+		}
+	}
+
+	// -------------------------------------------------------------------------
+	// The Tenant name must be free (or already belong to this request)
+	// -------------------------------------------------------------------------
+	existing := &platformv1alpha1.Tenant{}
+	err := r.Get(ctx, client.ObjectKey{Name: treq.Spec.Namespace}, existing)
+	switch {
+	case err == nil:
+		if existing.Annotations[platformv1alpha1.TenantRequestAnnotation] == requestKey(treq) {
+			return platformv1alpha1.TenantRequestProvisioned, "", nil
+		}
+		return denied("tenant %q already exists", treq.Spec.Namespace)
+	case !apierrors.IsNotFound(err):
+		return "", "", err
+	}
+
+	// -------------------------------------------------------------------------
+	// The namespace must not exist: a request never adopts a namespace (the
+	// one of a Tenant created for this request was accepted above)
+	// -------------------------------------------------------------------------
+	ns := &corev1.Namespace{}
+	err = r.reader().Get(ctx, client.ObjectKey{Name: treq.Spec.Namespace}, ns)
+	switch {
+	case err == nil:
+		return denied("namespace %q already exists", treq.Spec.Namespace)
+	case !apierrors.IsNotFound(err):
+		return "", "", err
+	}
+
+	// -------------------------------------------------------------------------
+	// Per-group cap, counted from the API server: the cache may not hold yet
+	// the Tenant created for the previous request of the group
+	// -------------------------------------------------------------------------
+	if selfService.MaxTenantsPerGroup > 0 {
+		owned, err := r.countOwnedTenants(ctx, treq.Spec.Group)
+		if err != nil {
+			return "", "", err
+		}
+
+		if owned >= int(selfService.MaxTenantsPerGroup) {
+			return denied(
+				"group %q already owns %d tenants (limit %d)",
+				treq.Spec.Group, owned, selfService.MaxTenantsPerGroup,
+			)
+		}
+	}
+
+	// -------------------------------------------------------------------------
+	// Approval: the annotations are only trusted behind the webhook
+	// -------------------------------------------------------------------------
+	switch {
+	case selfService.AutoApprove:
+		treq.Status.Approver = autoApprover
+	case !r.WebhookEnabled:
+		return platformv1alpha1.TenantRequestPending,
+			"manual approval requires the TenantRequest webhook, enable it or auto-approval", nil
+	case treq.Annotations[platformv1alpha1.TenantRequestApprovedAnnotation] == "true":
+		treq.Status.Approver = treq.Annotations[platformv1alpha1.TenantRequestApprovedByAnnotation]
+	default:
+		return platformv1alpha1.TenantRequestPending, "waiting for approval", nil
+	}
+
+	return platformv1alpha1.TenantRequestProvisioned, "", nil
+}
+
+// -----------------------------------------------------------------------------
+// createTenant creates the Tenant for an approved request. The owning group is
+// granted admin in the namespace; requester and approver are kept as
+// annotations on the Tenant for auditing.
+// -----------------------------------------------------------------------------
+func (r *TenantRequestReconciler) createTenant(
+	ctx context.Context,
+	treq *platformv1alpha1.TenantRequest,
+) error {
+
+	profile := treq.Spec.Profile
+
+	access := []platformv1alpha1.AccessBinding{
+		{
+			Role:   "admin",
+			Groups: []string{treq.Spec.Group},
+		},
+	}
+	access = append(access, treq.Spec.Access...)
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: treq.Spec.Namespace,
+			Annotations: map[string]string{
+				platformv1alpha1.TenantRequestAnnotation:           requestKey(treq),
+				platformv1alpha1.OwnerGroupAnnotation:              treq.Spec.Group,
+				platformv1alpha1.TenantRequestRequesterAnnotation:  treq.Status.Requester,
+				platformv1alpha1.TenantRequestApprovedByAnnotation: treq.Status.Approver,
+			},
+		},
+		Spec: platformv1alpha1.TenantSpec{
+			Namespace:         treq.Spec.Namespace,
+			Profile:           &profile,
+			Access:            access,
+			NamespaceMetadata: treq.Spec.NamespaceMetadata,
+		},
+	}
+
+	if err := r.Create(ctx, tenant); err != nil {
+		return client.IgnoreAlreadyExists(err)
+	}
+	audit.Record(ctx, audit.Create, platformv1alpha1.TenantRequestProvisioned, nil, tenant)
+
+	return nil
+}
+
+// countOwnedTenants returns the number of Tenants owned by group. Only the
+// metadata of the Tenants is read.
+func (r *TenantRequestReconciler) countOwnedTenants(ctx context.Context, group string) (int, error) {
+	tenants := &metav1.PartialObjectMetadataList{}
+	tenants.SetGroupVersionKind(platformv1alpha1.GroupVersion.WithKind("TenantList"))
+	if err := r.reader().List(ctx, tenants); err != nil {
+		return 0, err
+	}
+
+	owned := 0
+	for _, tenant := range tenants.Items {
+		if tenant.Annotations[platformv1alpha1.OwnerGroupAnnotation] == group {
+			owned++
+		}
+	}
+	return owned, nil
+}
+
+// reader returns the client reading around the cache
+func (r *TenantRequestReconciler) reader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// requestKey identifies a TenantRequest in the annotation of its Tenant.
+func requestKey(treq *platformv1alpha1.TenantRequest) string {
+	return treq.Namespace + "/" + treq.Name
+}
+
+// -----------------------------------------------------------------------------
+
+func (r *TenantRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+
+	// Requests are evaluated one at a time, so two requests of a group never
+	// pass the per-group cap together
+	options := controllerOptions(r.Config.Get().Controllers.TenantRequest)
+	options.MaxConcurrentReconciles = 1
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&platformv1alpha1.TenantRequest{}).
+		WithOptions(options).
+		Complete(r)
+}
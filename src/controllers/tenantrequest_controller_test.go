@@ -0,0 +1,304 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+
+	. "github.com/onsi/gomega"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestTenantRequestApprovalAndCap(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	profile := &platformv1alpha1.TenantProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "sandbox"},
+		Spec: platformv1alpha1.TenantProfileSpec{
+			SelfService: &platformv1alpha1.SelfServiceSpec{
+				Enabled:            true,
+				AllowedGroups:      []string{"team-a"},
+				MaxTenantsPerGroup: 1,
+			},
+		},
+	}
+
+	request := func(name, group string) *platformv1alpha1.TenantRequest {
+		return &platformv1alpha1.TenantRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "requests",
+				Annotations: map[string]string{
+					platformv1alpha1.TenantRequestRequesterAnnotation: "alice",
+				},
+			},
+			Spec: platformv1alpha1.TenantRequestSpec{
+				Namespace: name,
+				Profile:   "sandbox",
+				Group:     group,
+			},
+		}
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			profile,
+			request("team-a-one", "team-a"),
+			request("team-a-two", "team-a"),
+			request("team-b-one", "team-b"),
+			request("kube-flannel", "team-a"),
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-flannel"}},
+		).
+		WithStatusSubresource(&platformv1alpha1.TenantRequest{}).
+		Build()
+
+	r := &TenantRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10), WebhookEnabled: true}
+
+	reconcile := func(name string) *platformv1alpha1.TenantRequest {
+		key := client.ObjectKey{Namespace: "requests", Name: name}
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		treq := &platformv1alpha1.TenantRequest{}
+		g.Expect(c.Get(ctx, key, treq)).To(Succeed())
+		return treq
+	}
+
+	// -------------------------------------------------------------------------
+	// Pending until approved
+	// -------------------------------------------------------------------------
+	treq := reconcile("team-a-one")
+	g.Expect(treq.Status.Phase).To(Equal(platformv1alpha1.TenantRequestPending))
+	g.Expect(treq.Status.Requester).To(Equal("alice"))
+
+	treq.Annotations[platformv1alpha1.TenantRequestApprovedAnnotation] = "true"
+	treq.Annotations[platformv1alpha1.TenantRequestApprovedByAnnotation] = "bob"
+	g.Expect(c.Update(ctx, treq)).To(Succeed())
+
+	treq = reconcile("team-a-one")
+	g.Expect(treq.Status.Phase).To(Equal(platformv1alpha1.TenantRequestProvisioned))
+	g.Expect(treq.Status.Approver).To(Equal("bob"))
+	g.Expect(treq.Status.Requester).To(Equal("alice"))
+	g.Expect(treq.Status.Tenant).To(Equal("team-a-one"))
+
+	tenant := &platformv1alpha1.Tenant{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: "team-a-one"}, tenant)).To(Succeed())
+	g.Expect(*tenant.Spec.Profile).To(Equal("sandbox"))
+	g.Expect(tenant.Spec.Access).To(ContainElement(platformv1alpha1.AccessBinding{
+		Role:   "admin",
+		Groups: []string{"team-a"},
+	}))
+	g.Expect(tenant.Annotations).To(HaveKeyWithValue(platformv1alpha1.OwnerGroupAnnotation, "team-a"))
+
+	// -------------------------------------------------------------------------
+	// Per-group cap and allowed groups
+	// -------------------------------------------------------------------------
+	treq = reconcile("team-a-two")
+	g.Expect(treq.Status.Phase).To(Equal(platformv1alpha1.TenantRequestDenied))
+	g.Expect(treq.Status.Message).To(ContainSubstring("limit 1"))
+
+	treq = reconcile("team-b-one")
+	g.Expect(treq.Status.Phase).To(Equal(platformv1alpha1.TenantRequestDenied))
+	g.Expect(treq.Status.Message).To(ContainSubstring("may not use profile"))
+
+	// -------------------------------------------------------------------------
+	// Existing namespaces are never adopted
+	// -------------------------------------------------------------------------
+	treq = reconcile("kube-flannel")
+	g.Expect(treq.Status.Phase).To(Equal(platformv1alpha1.TenantRequestDenied))
+	g.Expect(treq.Status.Message).To(ContainSubstring("already exists"))
+}
+
+func TestTenantRequestAccess(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	profile := &platformv1alpha1.TenantProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "sandbox"},
+		Spec: platformv1alpha1.TenantProfileSpec{
+			SelfService: &platformv1alpha1.SelfServiceSpec{
+				Enabled:      true,
+				AutoApprove:  true,
+				AllowedRoles: []string{"view"},
+			},
+		},
+	}
+
+	request := func(name string, access ...platformv1alpha1.AccessBinding) *platformv1alpha1.TenantRequest {
+		return &platformv1alpha1.TenantRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "requests"},
+			Spec: platformv1alpha1.TenantRequestSpec{
+				Namespace: name,
+				Profile:   "sandbox",
+				Group:     "team-a",
+				Access:    access,
+			},
+		}
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			profile,
+			request("viewers", platformv1alpha1.AccessBinding{Role: "view", Groups: []string{"auditors"}}),
+			request("admins", platformv1alpha1.AccessBinding{Role: "cluster-admin", Groups: []string{"team-a"}}),
+			request("foreign", platformv1alpha1.AccessBinding{
+				Role:            "view",
+				ServiceAccounts: []platformv1alpha1.ServiceAccountReference{{Name: "ci", Namespace: "kube-system"}},
+			}),
+		).
+		WithStatusSubresource(&platformv1alpha1.TenantRequest{}).
+		Build()
+
+	r := &TenantRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}
+
+	for name, phase := range map[string]string{
+		"viewers": platformv1alpha1.TenantRequestProvisioned,
+		"admins":  platformv1alpha1.TenantRequestDenied,
+		"foreign": platformv1alpha1.TenantRequestDenied,
+	} {
+		key := client.ObjectKey{Namespace: "requests", Name: name}
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		treq := &platformv1alpha1.TenantRequest{}
+		g.Expect(c.Get(ctx, key, treq)).To(Succeed())
+		g.Expect(treq.Status.Phase).To(Equal(phase), name)
+	}
+}
+
+func TestTenantRequestWithoutWebhook(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	profile := &platformv1alpha1.TenantProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "sandbox"},
+		Spec: platformv1alpha1.TenantProfileSpec{
+			SelfService: &platformv1alpha1.SelfServiceSpec{Enabled: true},
+		},
+	}
+
+	// Annotations set by the user, not by the webhook
+	treq := &platformv1alpha1.TenantRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "team-a-one",
+			Namespace: "requests",
+			Annotations: map[string]string{
+				platformv1alpha1.TenantRequestRequesterAnnotation:  "alice",
+				platformv1alpha1.TenantRequestApprovedAnnotation:   "true",
+				platformv1alpha1.TenantRequestApprovedByAnnotation: "bob",
+			},
+		},
+		Spec: platformv1alpha1.TenantRequestSpec{
+			Namespace: "team-a-one",
+			Profile:   "sandbox",
+			Group:     "team-a",
+		},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile, treq).
+		WithStatusSubresource(&platformv1alpha1.TenantRequest{}).
+		Build()
+
+	r := &TenantRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}
+
+	key := client.ObjectKeyFromObject(treq)
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(c.Get(ctx, key, treq)).To(Succeed())
+	g.Expect(treq.Status.Phase).To(Equal(platformv1alpha1.TenantRequestPending))
+	g.Expect(treq.Status.Message).To(ContainSubstring("requires the TenantRequest webhook"))
+	g.Expect(treq.Status.Requester).To(BeEmpty())
+	g.Expect(treq.Status.Approver).To(BeEmpty())
+
+	err = c.Get(ctx, client.ObjectKey{Name: "team-a-one"}, &platformv1alpha1.Tenant{})
+	g.Expect(err).To(HaveOccurred())
+
+	// Group restrictions cannot be enforced either, even when auto-approved
+	profile.Spec.SelfService.AutoApprove = true
+	profile.Spec.SelfService.AllowedGroups = []string{"team-a"}
+	g.Expect(c.Update(ctx, profile)).To(Succeed())
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(c.Get(ctx, key, treq)).To(Succeed())
+	g.Expect(treq.Status.Phase).To(Equal(platformv1alpha1.TenantRequestPending))
+	g.Expect(treq.Status.Message).To(ContainSubstring("restricts groups"))
+
+	err = c.Get(ctx, client.ObjectKey{Name: "team-a-one"}, &platformv1alpha1.Tenant{})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestTenantRequestRejectedTenant(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	profile := &platformv1alpha1.TenantProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "sandbox"},
+		Spec: platformv1alpha1.TenantProfileSpec{
+			SelfService: &platformv1alpha1.SelfServiceSpec{
+				Enabled:      true,
+				AutoApprove:  true,
+				AllowedRoles: []string{"cluster-admin"},
+			},
+		},
+	}
+
+	treq := &platformv1alpha1.TenantRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "requests"},
+		Spec: platformv1alpha1.TenantRequestSpec{
+			Namespace: "team-a",
+			Profile:   "sandbox",
+			Group:     "team-a",
+			Access:    []platformv1alpha1.AccessBinding{{Role: "cluster-admin", Groups: []string{"team-a"}}},
+		},
+	}
+
+	// The Tenant webhook rejects roles the operator may not bind
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile, treq).
+		WithStatusSubresource(&platformv1alpha1.TenantRequest{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				return apierrors.NewInvalid(
+					platformv1alpha1.GroupVersion.WithKind("Tenant").GroupKind(),
+					obj.GetName(),
+					field.ErrorList{field.NotSupported(
+						field.NewPath("spec", "access").Index(1).Child("role"), "cluster-admin", []string{"admin"},
+					)},
+				)
+			},
+		}).
+		Build()
+
+	// Without a Recorder the Denied event is dropped
+	r := &TenantRequestReconciler{Client: c}
+
+	key := client.ObjectKeyFromObject(treq)
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(c.Get(ctx, key, treq)).To(Succeed())
+	g.Expect(treq.Status.Phase).To(Equal(platformv1alpha1.TenantRequestDenied))
+	g.Expect(treq.Status.Message).To(ContainSubstring("spec.access[1].role"))
+	g.Expect(treq.Status.Approver).To(BeEmpty())
+	g.Expect(treq.Status.Tenant).To(BeEmpty())
+}
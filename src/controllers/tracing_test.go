@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	. "github.com/onsi/gomega"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTenantReconcileSpans(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider, err := tracing.NewProvider(ctx, sdktrace.WithSpanProcessor(recorder))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	profile := "missing"
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "traced"},
+		Spec: platformv1alpha1.TenantSpec{
+			Namespace: "traced-ns",
+			Profile:   &profile,
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tenant).Build()
+	r := &TenantReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+
+	// The missing profile ends the reconcile as an invalid configuration
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tenant)})
+	g.Expect(reconcileOutcome(ctrl.Result{}, err)).To(Equal(OutcomeInvalidConfig))
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	g.Expect(spans).To(HaveKey("Tenant.Reconcile"))
+	g.Expect(spans).To(HaveKey("ResolveConfig"))
+
+	root, child := spans["Tenant.Reconcile"], spans["ResolveConfig"]
+	g.Expect(child.Parent().SpanID()).To(Equal(root.SpanContext().SpanID()))
+	g.Expect(child.Attributes()).To(ContainElement(tracing.ProfileKey.String("missing")))
+	g.Expect(root.Attributes()).To(ContainElements(
+		tracing.TenantKey.String("traced"),
+		tracing.NamespaceKey.String("traced-ns"),
+		tracing.ProfileKey.String("missing"),
+	))
+	g.Expect(root.Status().Code).To(Equal(codes.Error))
+}
@@ -0,0 +1,170 @@
+// Package features holds the feature gates of the operator, so new or risky
+// behaviour can be rolled out, or turned off, per cluster.
+package features
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Feature is the name of a feature gate
+type Feature string
+
+// Feature gates
+const (
+	// Webhooks serves the admission webhooks when webhook.enabled is set
+	Webhooks Feature = "Webhooks"
+
+	// Pruning deletes the managed objects a tenant no longer needs (resource
+	// templates, bootstrap copies, RoleBindings, baseline NetworkPolicies);
+	// when off they are left in place, still tracked, until it is turned on
+	Pruning Feature = "Pruning"
+
+	// Hibernation applies spec.schedule; when off tenants stay awake
+	Hibernation Feature = "Hibernation"
+
+	// Expiration deletes tenants past spec.expiresAt or spec.ttl
+	Expiration Feature = "Expiration"
+
+	// PriorityQueue reconciles changed objects before the unchanged ones
+	// queued by the initial list and periodic resyncs
+	PriorityQueue Feature = "PriorityQueue"
+)
+
+// Stage is the maturity of a feature
+type Stage string
+
+const (
+	Alpha Stage = "Alpha"
+	Beta  Stage = "Beta"
+	GA    Stage = "GA"
+)
+
+// Spec describes a feature gate
+type Spec struct {
+	Default bool
+	Stage   Stage
+}
+
+// known lists every feature gate with its default
+var known = map[Feature]Spec{
+	Webhooks:      {Default: true, Stage: Beta},
+	Pruning:       {Default: true, Stage: Beta},
+	Hibernation:   {Default: true, Stage: Beta},
+	Expiration:    {Default: true, Stage: Beta},
+	PriorityQueue: {Default: false, Stage: Alpha},
+}
+
+// FeatureEnabled exports the state of every feature gate
+var FeatureEnabled = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "namespace_operator_feature_enabled",
+		Help: "Whether a feature gate is enabled (1) or not (0)",
+	},
+	[]string{"feature", "stage"},
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(FeatureEnabled)
+}
+
+// Known returns the names of every feature gate, sorted
+func Known() []Feature {
+	names := make([]Feature, 0, len(known))
+	for f := range known {
+		names = append(names, f)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// -----------------------------------------------------------------------------
+// Gates is the state of the feature gates. A nil Gates has every feature at
+// its default.
+// -----------------------------------------------------------------------------
+type Gates struct {
+	enabled map[Feature]bool
+}
+
+// New returns the gates with the given overrides, rejecting unknown features
+func New(overrides map[string]bool) (*Gates, error) {
+
+	g := &Gates{enabled: map[Feature]bool{}}
+	for f, spec := range known {
+		g.enabled[f] = spec.Default
+	}
+
+	var unknown []string
+	for name, on := range overrides {
+		if _, ok := known[Feature(name)]; !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		g.enabled[Feature(name)] = on
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return nil, fmt.Errorf("unknown feature gates: %s", strings.Join(unknown, ", "))
+	}
+
+	return g, nil
+}
+
+// Enabled reports whether f is enabled
+func (g *Gates) Enabled(f Feature) bool {
+	if g == nil {
+		return known[f].Default
+	}
+	return g.enabled[f]
+}
+
+// RecordMetrics exports the state of every feature gate
+func (g *Gates) RecordMetrics() {
+	for _, f := range Known() {
+		value := 0.0
+		if g.Enabled(f) {
+			value = 1
+		}
+		FeatureEnabled.WithLabelValues(string(f), string(known[f].Stage)).Set(value)
+	}
+}
+
+// String lists the gates as Name=true,Other=false
+func (g *Gates) String() string {
+	var parts []string
+	for _, f := range Known() {
+		parts = append(parts, string(f)+"="+strconv.FormatBool(g.Enabled(f)))
+	}
+	return strings.Join(parts, ",")
+}
+
+// -----------------------------------------------------------------------------
+// Parse reads a --feature-gates value: comma separated Name=bool pairs.
+// -----------------------------------------------------------------------------
+func Parse(value string) (map[string]bool, error) {
+
+	gates := map[string]bool{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid feature gate %q, expected Name=true|false", pair)
+		}
+		on, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid feature gate %q: %w", pair, err)
+		}
+		gates[strings.TrimSpace(name)] = on
+	}
+
+	return gates, nil
+}
@@ -0,0 +1,45 @@
+package features
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestGates(t *testing.T) {
+	g := NewWithT(t)
+
+	var defaults *Gates
+	g.Expect(defaults.Enabled(Pruning)).To(BeTrue())
+
+	gates, err := New(map[string]bool{"Pruning": false})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gates.Enabled(Pruning)).To(BeFalse())
+	g.Expect(gates.Enabled(Hibernation)).To(BeTrue())
+	g.Expect(gates.Enabled(PriorityQueue)).To(BeFalse())
+	g.Expect(gates.String()).To(ContainSubstring("Pruning=false"))
+
+	_, err = New(map[string]bool{"Pruning": true, "Teleport": true})
+	g.Expect(err).To(MatchError(ContainSubstring("Teleport")))
+
+	gates.RecordMetrics()
+	g.Expect(testutil.ToFloat64(FeatureEnabled.WithLabelValues("Pruning", "Beta"))).To(BeZero())
+	g.Expect(testutil.ToFloat64(FeatureEnabled.WithLabelValues("Expiration", "Beta"))).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(FeatureEnabled.WithLabelValues("PriorityQueue", "Alpha"))).To(BeZero())
+}
+
+func TestParse(t *testing.T) {
+	g := NewWithT(t)
+
+	gates, err := Parse("Pruning=false, Hibernation=true,")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gates).To(Equal(map[string]bool{"Pruning": false, "Hibernation": true}))
+
+	_, err = Parse("Pruning")
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = Parse("Pruning=maybe")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,157 @@
+// Package health provides the readiness and liveness checks of the operator.
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// syncTimeout bounds how long a readiness probe waits for the informers
+const syncTimeout = time.Second
+
+// -----------------------------------------------------------------------------
+// CacheSynced is ready once the informers of the manager cache have synced,
+// so the operator does not report ready while it still reads an empty cache.
+// -----------------------------------------------------------------------------
+func CacheSynced(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), syncTimeout)
+		defer cancel()
+
+		if !c.WaitForCacheSync(ctx) {
+			return errors.New("informers have not synced yet")
+		}
+		return nil
+	}
+}
+
+// -----------------------------------------------------------------------------
+// WebhookCertificate is ready once the webhook serving certificate in dir
+// can be loaded and is valid, which it is not until cert-manager (or another
+// issuer) has written it.
+// -----------------------------------------------------------------------------
+func WebhookCertificate(dir, certName, keyName string) healthz.Checker {
+	return func(_ *http.Request) error {
+		pair, err := tls.LoadX509KeyPair(filepath.Join(dir, certName), filepath.Join(dir, keyName))
+		if err != nil {
+			return fmt.Errorf("webhook certificate: %w", err)
+		}
+
+		cert, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			return fmt.Errorf("webhook certificate: %w", err)
+		}
+
+		now := time.Now()
+		if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+			return fmt.Errorf("webhook certificate is only valid from %s to %s",
+				cert.NotBefore.Format(time.RFC3339), cert.NotAfter.Format(time.RFC3339))
+		}
+		return nil
+	}
+}
+
+// -----------------------------------------------------------------------------
+// StallDetector fails liveness when a controller has had work queued for
+// longer than Window without completing a reconcile: its workers are wedged
+// and only a restart gets it going again. Queues and reconciles are read from
+// the controller-runtime metrics (workqueue_depth and
+// controller_runtime_reconcile_total) on each probe.
+// -----------------------------------------------------------------------------
+type StallDetector struct {
+	// How long work may stay queued without a reconcile completing
+	Window time.Duration
+
+	// Registry holding the controller-runtime metrics
+	Gatherer prometheus.Gatherer
+
+	mu       sync.Mutex
+	progress map[string]progress
+
+	// now is overridden in tests
+	now func() time.Time
+}
+
+// progress is the last sign of life of a controller
+type progress struct {
+	reconciles float64
+	since      time.Time
+}
+
+// Check is the healthz.Checker of the detector
+func (d *StallDetector) Check(_ *http.Request) error {
+
+	families, err := d.Gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("unable to read controller metrics: %w", err)
+	}
+
+	depth := map[string]float64{}
+	reconciles := map[string]float64{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			var controller string
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "controller" {
+					controller = label.GetValue()
+				}
+			}
+			if controller == "" {
+				continue
+			}
+
+			switch family.GetName() {
+			case "workqueue_depth":
+				depth[controller] += m.GetGauge().GetValue()
+			case "controller_runtime_reconcile_total":
+				reconciles[controller] += m.GetCounter().GetValue()
+			}
+		}
+	}
+
+	now := time.Now()
+	if d.now != nil {
+		now = d.now()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.progress == nil {
+		d.progress = map[string]progress{}
+	}
+
+	// An empty queue or a completed reconcile restarts the window
+	var stalled []string
+	for controller, queued := range depth {
+		last, seen := d.progress[controller]
+		if !seen || queued == 0 || reconciles[controller] != last.reconciles {
+			d.progress[controller] = progress{reconciles: reconciles[controller], since: now}
+			continue
+		}
+		if now.Sub(last.since) > d.Window {
+			stalled = append(stalled, controller)
+		}
+	}
+
+	if len(stalled) == 0 {
+		return nil
+	}
+	slices.Sort(stalled)
+	return fmt.Errorf("no reconcile completed for %s with work queued: %s",
+		d.Window, strings.Join(stalled, ", "))
+}
@@ -0,0 +1,127 @@
+package health
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+)
+
+func TestCacheSynced(t *testing.T) {
+	g := NewWithT(t)
+
+	synced := false
+	check := CacheSynced(&informertest.FakeInformers{Synced: &synced})
+	req := httptest.NewRequest("GET", "/readyz", nil)
+
+	g.Expect(check(req)).To(MatchError(ContainSubstring("not synced")))
+
+	synced = true
+	g.Expect(check(req)).To(Succeed())
+}
+
+func TestStallDetector(t *testing.T) {
+	g := NewWithT(t)
+
+	registry := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "workqueue_depth",
+	}, []string{"name", "controller", "priority"})
+	reconciles := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_reconcile_total",
+	}, []string{"controller", "result"})
+	registry.MustRegister(depth, reconciles)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := &StallDetector{
+		Window:   time.Minute,
+		Gatherer: registry,
+		now:      func() time.Time { return now },
+	}
+	advance := func(by time.Duration) { now = now.Add(by) }
+
+	// Idle controllers are live however long they wait
+	depth.WithLabelValues("tenant", "tenant", "0").Set(0)
+	g.Expect(d.Check(nil)).To(Succeed())
+	advance(time.Hour)
+	g.Expect(d.Check(nil)).To(Succeed())
+
+	// Work queued and reconciles completing
+	depth.WithLabelValues("tenant", "tenant", "0").Set(3)
+	g.Expect(d.Check(nil)).To(Succeed())
+	advance(50 * time.Second)
+	reconciles.WithLabelValues("tenant", "success").Inc()
+	g.Expect(d.Check(nil)).To(Succeed())
+	advance(50 * time.Second)
+	g.Expect(d.Check(nil)).To(Succeed())
+
+	// No reconcile completes for longer than the window
+	advance(20 * time.Second)
+	g.Expect(d.Check(nil)).To(MatchError(ContainSubstring("tenant")))
+
+	// Failed reconciles still show progress
+	reconciles.WithLabelValues("tenant", "error").Inc()
+	g.Expect(d.Check(nil)).To(Succeed())
+}
+
+func TestWebhookCertificate(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	check := WebhookCertificate(dir, "tls.crt", "tls.key")
+
+	g.Expect(check(nil)).To(MatchError(ContainSubstring("webhook certificate")))
+
+	writeCertificate(t, dir, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	g.Expect(check(nil)).To(Succeed())
+
+	writeCertificate(t, dir, time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour))
+	g.Expect(check(nil)).To(MatchError(ContainSubstring("only valid")))
+}
+
+// writeCertificate writes a self-signed certificate and its key to dir
+func writeCertificate(t *testing.T, dir string, notBefore, notAfter time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "webhook"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, block := range map[string]*pem.Block{
+		"tls.crt": {Type: "CERTIFICATE", Bytes: der},
+		"tls.key": {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
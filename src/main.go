@@ -1,23 +1,43 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"os"
-	"strconv"
+	_ "time/tzdata" // hibernation schedule time zones, whatever the base image
 
 	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/audit"
+	"github.com/tngs/namespace-operator/config"
 	"github.com/tngs/namespace-operator/controllers"
+	"github.com/tngs/namespace-operator/features"
+	"github.com/tngs/namespace-operator/health"
+	"github.com/tngs/namespace-operator/tracing"
+	"github.com/tngs/namespace-operator/webhooks"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 
 	networkingv1 "k8s.io/api/networking/v1" // 🔥 IMPORTANT
 
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
+	uberzap "go.uber.org/zap"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// Files of the webhook serving certificate in webhook.certDir
+const (
+	webhookCertName = "tls.crt"
+	webhookKeyName  = "tls.key"
 )
 
 var (
@@ -28,77 +48,278 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(networkingv1.AddToScheme(scheme))     // 🔥 REQUIRED
-	utilruntime.Must(platformv1alpha1.AddToScheme(scheme)) // Tenant & TenantProfile
+	utilruntime.Must(platformv1alpha1.AddToScheme(scheme)) // Tenant, TenantProfile & TenantRequest
+}
+
+// loadConfig reads the configuration file, then the environment and flags
+func loadConfig(flags *config.Flags) (*config.OperatorConfig, error) {
+	cfg, err := config.Load(flags.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	flags.Apply(cfg)
+	return cfg, nil
 }
 
 func main() {
-	devMode := false
-	if v, ok := os.LookupEnv("LOG_DEV_MODE"); ok {
-		parsed, err := strconv.ParseBool(v)
-		if err == nil {
-			devMode = parsed
+	os.Exit(run())
+}
+
+// run sets up and starts the manager; deferred cleanups, flushing traces and
+// closing the audit log, run before main exits with the returned code
+func run() int {
+	var flags config.Flags
+	flags.Bind(flag.CommandLine)
+	flag.Parse()
+
+	cfg, err := loadConfig(&flags)
+	if err == nil {
+		err = cfg.Validate()
+	}
+
+	// The log level follows configuration reloads, the format needs a restart
+	logging := config.Default().Logging
+	if err == nil {
+		logging = cfg.Logging
+	}
+	logLevel := uberzap.NewAtomicLevel()
+	_ = logLevel.UnmarshalText([]byte(logging.Level))
+	logOpts := []zap.Opts{zap.Level(logLevel), zap.JSONEncoder()}
+	if logging.Format == config.LogFormatConsole {
+		logOpts = append(logOpts, zap.ConsoleEncoder())
+	}
+	ctrl.SetLogger(zap.New(logOpts...))
+
+	if err != nil {
+		setupLog.Error(err, "invalid configuration", "file", flags.ConfigFile)
+		return 1
+	}
+	store := config.NewStore(cfg)
+
+	// Validated above, feature gates need a restart
+	gates, _ := features.New(cfg.FeatureGates)
+	gates.RecordMetrics()
+	setupLog.Info("feature gates", "gates", gates.String())
+	if cfg.Sharding.Enabled() {
+		setupLog.Info("sharding", "shard", cfg.Sharding.Shard, "shards", cfg.Sharding.Shards,
+			"lease", cfg.LeaderElectionID())
+	}
+
+	// OTLP exporter configured by the standard OTEL_* variables, if any
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		return 1
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "unable to flush traces")
+		}
+	}()
+
+	// Audit log of the changes made to the cluster: "stdout" or a file path
+	var auditSink audit.Sink
+	if target := cfg.AuditLog; target != "" {
+		sink, closer, err := audit.Open(target)
+		if err != nil {
+			setupLog.Error(err, "unable to open audit log", "target", target)
+			return 1
 		}
+		defer closer.Close()
+		auditSink = sink
 	}
-	ctrl.SetLogger(zap.New(zap.UseDevMode(devMode)))
 
-	leaderElection := os.Getenv("ENABLE_LEADER_ELECTION") == "true"
-	leaderElectionNamespace := os.Getenv("LEADER_ELECTION_NAMESPACE")
+	restConfig := ctrl.GetConfigOrDie()
+	restConfig.QPS = cfg.Client.QPS
+	restConfig.Burst = cfg.Client.Burst
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	cacheOpts := cache.Options{
+		SyncPeriod: &cfg.SyncPeriod.Duration,
+	}
+	cacheOpts = controllers.CacheOptions(cacheOpts, cfg.Bootstrap.SourceNamespaces)
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme: scheme,
 
 		Metrics: metricsserver.Options{
-			BindAddress: ":8080",
+			BindAddress: cfg.Metrics.BindAddress,
 		},
 
-		HealthProbeBindAddress: ":8081",
+		HealthProbeBindAddress: cfg.Health.BindAddress,
+
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:     cfg.Webhook.Port,
+			CertDir:  cfg.Webhook.CertDir,
+			CertName: webhookCertName,
+			KeyName:  webhookKeyName,
+		}),
+
+		LeaderElection:          cfg.LeaderElection.Enabled,
+		LeaderElectionID:        cfg.LeaderElectionID(),
+		LeaderElectionNamespace: cfg.LeaderElection.Namespace,
 
-		LeaderElection:          leaderElection,
-		LeaderElectionID:        "namespace-operator.platform.example.com",
-		LeaderElectionNamespace: leaderElectionNamespace,
+		Cache: cacheOpts,
+
+		// Changes are reconciled before the periodic resync backlog
+		Controller: ctrlconfig.Controller{
+			UsePriorityQueue: ptr.To(gates.Enabled(features.PriorityQueue)),
+		},
 	})
 
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
-		os.Exit(1)
+		return 1
+	}
+
+	// ---------------------------------------------------------------------
+	// Configuration reload
+	// ---------------------------------------------------------------------
+	if flags.ConfigFile != "" {
+		if err := mgr.Add(&config.Watcher{
+			Path:  flags.ConfigFile,
+			Store: store,
+			Load:  func() (*config.OperatorConfig, error) { return loadConfig(&flags) },
+			OnReload: func(cfg *config.OperatorConfig) {
+				_ = logLevel.UnmarshalText([]byte(cfg.Logging.Level))
+			},
+			Log: ctrl.Log.WithName("config"),
+		}); err != nil {
+			setupLog.Error(err, "unable to watch configuration", "file", flags.ConfigFile)
+			return 1
+		}
 	}
 
 	// ---------------------------------------------------------------------
 	// Tenant controller
 	// ---------------------------------------------------------------------
 	if err = (&controllers.TenantReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  mgr.GetEventRecorderFor("namespace-operator"),
+
+		Config:   store,
+		Features: gates,
+		Audit:    auditSink,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Tenant")
-		os.Exit(1)
+		return 1
 	}
 
 	// ---------------------------------------------------------------------
 	// NetworkPolicy controller  🔥🔥🔥
 	// ---------------------------------------------------------------------
 	if err = (&controllers.NetworkPolicyReconciler{
-		Client: mgr.GetClient(),
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("namespace-operator"),
+		Config:   store,
+		Features: gates,
+		Audit:    auditSink,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "NetworkPolicy")
-		os.Exit(1)
+		return 1
+	}
+
+	// ---------------------------------------------------------------------
+	// TenantRequest controller (self-service), on the first shard only: the
+	// per-group cap counts the Tenants of every shard
+	// ---------------------------------------------------------------------
+	if cfg.Sharding.Shard != 0 {
+		setupLog.Info("TenantRequests are reconciled by shard 0", "shard", cfg.Sharding.Shard)
+	} else if err = (&controllers.TenantRequestReconciler{
+		Client:         mgr.GetClient(),
+		APIReader:      mgr.GetAPIReader(),
+		Recorder:       mgr.GetEventRecorderFor("namespace-operator"),
+		Config:         store,
+		WebhookEnabled: cfg.Webhook.Enabled && gates.Enabled(features.Webhooks),
+		Audit:          auditSink,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TenantRequest")
+		return 1
+	}
+
+	// ---------------------------------------------------------------------
+	// Admission webhooks (need serving certificates, see chart values)
+	// ---------------------------------------------------------------------
+	if cfg.Webhook.Enabled && gates.Enabled(features.Webhooks) {
+		if err = (&webhooks.TenantValidator{
+			Client: mgr.GetClient(),
+			Config: store,
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Tenant")
+			return 1
+		}
+
+		if err = (&webhooks.TenantRequestWebhook{
+			Client: mgr.GetClient(),
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TenantRequest")
+			return 1
+		}
+
+		if err = (&webhooks.PodPlacement{
+			Client: mgr.GetClient(),
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Pod")
+			return 1
+		}
+
+		if err = (&webhooks.ImageRegistryValidator{
+			Client: mgr.GetClient(),
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ImageRegistry")
+			return 1
+		}
 	}
 
 	// ---------------------------------------------------------------------
 	// Health probes
 	// ---------------------------------------------------------------------
-	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up health check", "check", "healthz")
-		os.Exit(1)
+	// Live while no controller is wedged with work queued, ready once the
+	// informers have synced and the webhook server can serve
+	healthChecks := map[string]healthz.Checker{
+		"healthz": healthz.Ping,
+	}
+	if window := cfg.Health.StallTimeout.Duration; window > 0 {
+		healthChecks["controllers"] = (&health.StallDetector{
+			Window:   window,
+			Gatherer: ctrlmetrics.Registry,
+		}).Check
 	}
 
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up ready check", "check", "readyz")
-		os.Exit(1)
+	readyChecks := map[string]healthz.Checker{
+		"informers": health.CacheSynced(mgr.GetCache()),
+	}
+	if cfg.Webhook.Enabled && gates.Enabled(features.Webhooks) {
+		readyChecks["webhook"] = mgr.GetWebhookServer().StartedChecker()
+		readyChecks["webhook-certificate"] = health.WebhookCertificate(
+			cfg.Webhook.CertDir,
+			webhookCertName,
+			webhookKeyName,
+		)
+	}
+
+	for name, check := range healthChecks {
+		if err := mgr.AddHealthzCheck(name, check); err != nil {
+			setupLog.Error(err, "unable to set up health check", "check", name)
+			return 1
+		}
+	}
+
+	for name, check := range readyChecks {
+		if err := mgr.AddReadyzCheck(name, check); err != nil {
+			setupLog.Error(err, "unable to set up ready check", "check", name)
+			return 1
+		}
 	}
 
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "unable to start manager")
-		os.Exit(1)
+		return 1
 	}
+
+	return 0
 }
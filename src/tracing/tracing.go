@@ -0,0 +1,124 @@
+// Package tracing sets up OpenTelemetry tracing for the operator.
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of the operator spans
+const TracerName = "github.com/tngs/namespace-operator"
+
+// ServiceName is reported unless OTEL_SERVICE_NAME is set
+const ServiceName = "namespace-operator"
+
+// Span attributes set on reconcile and apply spans
+const (
+	TenantKey    = attribute.Key("tenant")
+	NamespaceKey = attribute.Key("namespace")
+	ProfileKey   = attribute.Key("profile")
+	KindKey      = attribute.Key("kind")
+	NameKey      = attribute.Key("name")
+)
+
+// -----------------------------------------------------------------------------
+// Setup installs the global tracer provider. Spans are exported over OTLP
+// when OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is
+// set, using OTEL_EXPORTER_OTLP_PROTOCOL (grpc by default, or http/protobuf)
+// and the other standard OTEL_* variables; otherwise tracing stays disabled.
+// The returned function flushes pending spans on shutdown.
+// -----------------------------------------------------------------------------
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" &&
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch protocol() {
+	case "http/protobuf":
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		exporter, err = otlptracegrpc.New(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewProvider(ctx, sdktrace.WithBatcher(exporter))
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// -----------------------------------------------------------------------------
+// NewProvider returns a tracer provider describing the operator, with its
+// spans sent to the given processors. Tests pass an in-process recorder.
+// -----------------------------------------------------------------------------
+func NewProvider(
+	ctx context.Context,
+	opts ...sdktrace.TracerProviderOption,
+) (*sdktrace.TracerProvider, error) {
+
+	res, err := resource.New(
+		ctx,
+		resource.WithAttributes(semconv.ServiceName(ServiceName)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return sdktrace.NewTracerProvider(append(opts, sdktrace.WithResource(res))...), nil
+}
+
+// protocol returns the OTLP protocol configured for traces
+func protocol() string {
+	if p := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"); p != "" {
+		return p
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+}
+
+// -----------------------------------------------------------------------------
+// Start starts a span from the global tracer provider. The provider is looked
+// up on every call, so a provider installed later (or by a test) is used.
+// -----------------------------------------------------------------------------
+func Start(
+	ctx context.Context,
+	name string,
+	attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
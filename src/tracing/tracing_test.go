@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetupDisabledWithoutEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	previous := otel.GetTracerProvider()
+
+	shutdown, err := Setup(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if otel.GetTracerProvider() != previous {
+		t.Fatal("expected the global tracer provider to be left alone")
+	}
+}
+
+func TestStartAndEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider, err := NewProvider(context.Background(), sdktrace.WithSpanProcessor(recorder))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx, parent := Start(context.Background(), "parent", TenantKey.String("team-a"))
+	_, child := Start(ctx, "child")
+	End(child, errors.New("conflict"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Fatal("expected the child span to belong to the parent span")
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Fatalf("expected the child span to record the error, got %v", spans[0].Status())
+	}
+	if got := spans[1].Attributes(); len(got) != 1 || got[0] != TenantKey.String("team-a") {
+		t.Fatalf("unexpected parent attributes %v", got)
+	}
+	if name := spans[1].Resource().Attributes(); len(name) == 0 {
+		t.Fatal("expected the service resource attributes")
+	}
+}
@@ -0,0 +1,239 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/controllers"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ImageRegistryWebhookPath serves the image registry validation for Pods,
+// including the debug containers added through pods/ephemeralcontainers, and
+// every pod-templated workload kind.
+const ImageRegistryWebhookPath = "/validate-image-registries"
+
+// +kubebuilder:webhook:path=/validate-image-registries,mutating=false,failurePolicy=fail,sideEffects=None,groups="";apps;batch,resources=pods;pods/ephemeralcontainers;replicationcontrollers;deployments;replicasets;statefulsets;daemonsets;jobs;cronjobs,verbs=create;update,versions=v1,name=vimage-registries.platform.example.com,admissionReviewVersions=v1
+
+// -----------------------------------------------------------------------------
+// ImageRegistryValidator checks the images of Pods and pod templates in
+// tenant namespaces against the allowedRegistries of the tenant profile plus
+// the tenant registryExceptions. In Audit mode violations are only warned
+// about and counted.
+// -----------------------------------------------------------------------------
+type ImageRegistryValidator struct {
+	Client client.Reader
+
+	decoder admission.Decoder
+}
+
+var _ admission.Handler = &ImageRegistryValidator{}
+
+func (v *ImageRegistryValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	v.decoder = admission.NewDecoder(mgr.GetScheme())
+	mgr.GetWebhookServer().Register(ImageRegistryWebhookPath, &webhook.Admission{Handler: v})
+	return nil
+}
+
+func (v *ImageRegistryValidator) Handle(
+	ctx context.Context,
+	req admission.Request,
+) admission.Response {
+
+	spec, err := v.podSpec(req)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if spec == nil {
+		return admission.Allowed("")
+	}
+
+	tenant, profile, err := v.tenantFor(ctx, req.Namespace)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if profile == nil || len(profile.Spec.AllowedRegistries) == 0 {
+		return admission.Allowed("")
+	}
+
+	allowed := append(
+		append([]string{}, profile.Spec.AllowedRegistries...),
+		tenant.Spec.RegistryExceptions...,
+	)
+
+	var rejected []string
+	for _, image := range podImages(spec) {
+		if !imageAllowed(image, allowed) {
+			rejected = append(rejected, image)
+		}
+	}
+	if len(rejected) == 0 {
+		return admission.Allowed("")
+	}
+
+	message := fmt.Sprintf(
+		"images not from a registry allowed for tenant %s (%s): %s",
+		tenant.Name, strings.Join(allowed, ", "), strings.Join(rejected, ", "),
+	)
+
+	if profile.Spec.RegistryPolicy == platformv1alpha1.RegistryPolicyAudit {
+		ImageAdmissionsDenied.WithLabelValues(tenant.Name, "audit").Inc()
+		return admission.Allowed("").WithWarnings(message)
+	}
+
+	ImageAdmissionsDenied.WithLabelValues(tenant.Name, "enforce").Inc()
+	return admission.Denied(message)
+}
+
+// -----------------------------------------------------------------------------
+// podSpec decodes the admitted object and returns its pod spec, or nil for
+// kinds without one.
+// -----------------------------------------------------------------------------
+func (v *ImageRegistryValidator) podSpec(req admission.Request) (*corev1.PodSpec, error) {
+
+	var (
+		obj  runtime.Object
+		spec func() *corev1.PodSpec
+	)
+
+	switch req.Kind.Kind {
+	case "Pod":
+		o := &corev1.Pod{}
+		obj, spec = o, func() *corev1.PodSpec { return &o.Spec }
+	case "ReplicationController":
+		o := &corev1.ReplicationController{}
+		obj, spec = o, func() *corev1.PodSpec {
+			if o.Spec.Template == nil {
+				return nil
+			}
+			return &o.Spec.Template.Spec
+		}
+	case "Deployment":
+		o := &appsv1.Deployment{}
+		obj, spec = o, func() *corev1.PodSpec { return &o.Spec.Template.Spec }
+	case "ReplicaSet":
+		o := &appsv1.ReplicaSet{}
+		obj, spec = o, func() *corev1.PodSpec { return &o.Spec.Template.Spec }
+	case "StatefulSet":
+		o := &appsv1.StatefulSet{}
+		obj, spec = o, func() *corev1.PodSpec { return &o.Spec.Template.Spec }
+	case "DaemonSet":
+		o := &appsv1.DaemonSet{}
+		obj, spec = o, func() *corev1.PodSpec { return &o.Spec.Template.Spec }
+	case "Job":
+		o := &batchv1.Job{}
+		obj, spec = o, func() *corev1.PodSpec { return &o.Spec.Template.Spec }
+	case "CronJob":
+		o := &batchv1.CronJob{}
+		obj, spec = o, func() *corev1.PodSpec { return &o.Spec.JobTemplate.Spec.Template.Spec }
+	default:
+		return nil, nil
+	}
+
+	if err := v.decoder.Decode(req, obj); err != nil {
+		return nil, err
+	}
+
+	return spec(), nil
+}
+
+// -----------------------------------------------------------------------------
+// tenantFor returns the Tenant owning a namespace and its profile. Both are
+// nil for namespaces not managed by the operator or tenants without profile.
+// -----------------------------------------------------------------------------
+func (v *ImageRegistryValidator) tenantFor(
+	ctx context.Context,
+	namespace string,
+) (*platformv1alpha1.Tenant, *platformv1alpha1.TenantProfile, error) {
+
+	ns := &corev1.Namespace{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return nil, nil, client.IgnoreNotFound(err)
+	}
+
+	name := ns.Labels[controllers.TenantLabelKey]
+	if name == "" {
+		return nil, nil, nil
+	}
+
+	tenant := &platformv1alpha1.Tenant{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Name: name}, tenant); err != nil {
+		return nil, nil, client.IgnoreNotFound(err)
+	}
+	if tenant.Spec.Profile == nil {
+		return tenant, nil, nil
+	}
+
+	profile := &platformv1alpha1.TenantProfile{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Name: *tenant.Spec.Profile}, profile); err != nil {
+		if apierrors.IsNotFound(err) {
+			return tenant, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	return tenant, profile, nil
+}
+
+// podImages lists the images of every container of a pod spec.
+func podImages(spec *corev1.PodSpec) []string {
+	var images []string
+	for _, c := range spec.InitContainers {
+		images = append(images, c.Image)
+	}
+	for _, c := range spec.Containers {
+		images = append(images, c.Image)
+	}
+	for _, c := range spec.EphemeralContainers {
+		images = append(images, c.Image)
+	}
+	return images
+}
+
+// -----------------------------------------------------------------------------
+// imageAllowed reports whether an image reference comes from one of the
+// allowed registries or image prefixes. References without a registry are
+// resolved to docker.io like the container runtime does.
+// -----------------------------------------------------------------------------
+func imageAllowed(image string, allowed []string) bool {
+	ref := normalizeImage(image)
+	for _, entry := range allowed {
+		entry = strings.TrimSuffix(entry, "/")
+		if ref == entry || strings.HasPrefix(ref, entry+"/") {
+			return true
+		}
+		// A full repository also matches its tags and digests (a bare
+		// registry host must not match the same host on another port)
+		if strings.Contains(entry, "/") &&
+			(strings.HasPrefix(ref, entry+":") || strings.HasPrefix(ref, entry+"@")) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeImage prefixes short image references with docker.io (and
+// library/ for official images).
+func normalizeImage(image string) string {
+	first, rest, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return image
+	}
+	if !found {
+		return "docker.io/library/" + first
+	}
+	return "docker.io/" + first + "/" + rest
+}
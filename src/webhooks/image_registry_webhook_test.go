@@ -0,0 +1,135 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/controllers"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestImageAllowed(t *testing.T) {
+	g := NewWithT(t)
+
+	allowed := []string{"ghcr.io/my-org", "registry.example.com", "docker.io/library"}
+
+	g.Expect(imageAllowed("ghcr.io/my-org/app:1.0", allowed)).To(BeTrue())
+	g.Expect(imageAllowed("registry.example.com/team/app@sha256:abc", allowed)).To(BeTrue())
+	g.Expect(imageAllowed("nginx:1.27", allowed)).To(BeTrue())
+
+	g.Expect(imageAllowed("ghcr.io/other-org/app", allowed)).To(BeFalse())
+	g.Expect(imageAllowed("ghcr.io/my-org-evil/app", allowed)).To(BeFalse())
+	g.Expect(imageAllowed("registry.example.com:5000/app", allowed)).To(BeFalse())
+	g.Expect(imageAllowed("bitnami/redis", allowed)).To(BeFalse())
+}
+
+func TestImageRegistryValidator_EnforceAuditAndExceptions(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(appsv1.AddToScheme(scheme))
+	utilruntime.Must(platformv1alpha1.AddToScheme(scheme))
+
+	profileName := "standard"
+	profile := &platformv1alpha1.TenantProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: profileName},
+		Spec: platformv1alpha1.TenantProfileSpec{
+			AllowedRegistries: []string{"ghcr.io/my-org"},
+			RegistryPolicy:    platformv1alpha1.RegistryPolicyEnforce,
+		},
+	}
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec: platformv1alpha1.TenantSpec{
+			Namespace:          "team-a",
+			Profile:            &profileName,
+			RegistryExceptions: []string{"quay.io/team-a"},
+		},
+	}
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "team-a",
+			Labels: map[string]string{controllers.TenantLabelKey: "team-a"},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(profile, tenant, ns).Build()
+	v := &ImageRegistryValidator{Client: c, decoder: admission.NewDecoder(scheme)}
+
+	deployment := func(image string) admission.Request {
+		d := &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a"},
+		}
+		d.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: image}}
+		raw, err := json.Marshal(d)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			Namespace: "team-a",
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+
+	ctx := context.Background()
+
+	g.Expect(v.Handle(ctx, deployment("ghcr.io/my-org/app:1")).Allowed).To(BeTrue())
+	g.Expect(v.Handle(ctx, deployment("quay.io/team-a/tool")).Allowed).To(BeTrue())
+
+	denied := testutil.ToFloat64(ImageAdmissionsDenied.WithLabelValues("team-a", "enforce"))
+	resp := v.Handle(ctx, deployment("docker.io/evil/miner"))
+	g.Expect(resp.Allowed).To(BeFalse())
+	g.Expect(resp.Result.Message).To(ContainSubstring("docker.io/evil/miner"))
+	g.Expect(testutil.ToFloat64(ImageAdmissionsDenied.WithLabelValues("team-a", "enforce"))).To(Equal(denied + 1))
+
+	// Debug containers are added through the ephemeralcontainers subresource
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "ghcr.io/my-org/app:1"}},
+			EphemeralContainers: []corev1.EphemeralContainer{{
+				EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debug", Image: "docker.io/evil/shell"},
+			}},
+		},
+	}
+	raw, err := json.Marshal(pod)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	resp = v.Handle(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Kind:        metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		SubResource: "ephemeralcontainers",
+		Namespace:   "team-a",
+		Operation:   admissionv1.Update,
+		Object:      runtime.RawExtension{Raw: raw},
+	}})
+	g.Expect(resp.Allowed).To(BeFalse())
+	g.Expect(resp.Result.Message).To(ContainSubstring("docker.io/evil/shell"))
+
+	// Audit mode only warns
+	profile.Spec.RegistryPolicy = platformv1alpha1.RegistryPolicyAudit
+	g.Expect(c.Update(ctx, profile)).To(Succeed())
+
+	resp = v.Handle(ctx, deployment("docker.io/evil/miner"))
+	g.Expect(resp.Allowed).To(BeTrue())
+	g.Expect(resp.Warnings).To(HaveLen(1))
+	g.Expect(testutil.ToFloat64(ImageAdmissionsDenied.WithLabelValues("team-a", "audit"))).To(Equal(1.0))
+}
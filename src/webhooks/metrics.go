@@ -0,0 +1,22 @@
+package webhooks
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	ImageAdmissionsDenied = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "namespace_operator_image_admissions_denied_total",
+			Help: "Total number of admissions using images from registries not allowed for the tenant (mode=audit were only warned)",
+		},
+		[]string{"tenant", "mode"},
+	)
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		ImageAdmissionsDenied,
+	)
+}
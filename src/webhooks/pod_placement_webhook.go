@@ -0,0 +1,141 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod-placement.platform.example.com,admissionReviewVersions=v1
+
+// -----------------------------------------------------------------------------
+// PodPlacement injects the node selector and tolerations of the tenant node
+// pool into pods, for clusters without the PodNodeSelector and
+// PodTolerationRestriction admission plugins. The placement is read from the
+// scheduler annotations the operator writes on the namespace. Pods asking
+// for a conflicting node label or bound to a node directly are rejected, so
+// they cannot escape the pool.
+// -----------------------------------------------------------------------------
+type PodPlacement struct {
+	Client client.Reader
+}
+
+var _ admission.CustomDefaulter = &PodPlacement{}
+
+func (w *PodPlacement) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&corev1.Pod{}).
+		WithDefaulter(w).
+		Complete()
+}
+
+func (w *PodPlacement) Default(ctx context.Context, obj runtime.Object) error {
+
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return fmt.Errorf("expected a Pod but got %T", obj)
+	}
+
+	// The namespace is not always set on the object at creation
+	namespace := pod.Namespace
+	if namespace == "" {
+		req, err := admission.RequestFromContext(ctx)
+		if err != nil {
+			return err
+		}
+		namespace = req.Namespace
+	}
+
+	ns := &corev1.Namespace{}
+	if err := w.Client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	selector, err := labels.ConvertSelectorToLabelsMap(
+		ns.Annotations[platformv1alpha1.NodeSelectorAnnotation],
+	)
+	if err != nil {
+		return fmt.Errorf("namespace %s: invalid node selector: %w", namespace, err)
+	}
+
+	var tolerations []corev1.Toleration
+	if v := ns.Annotations[platformv1alpha1.DefaultTolerationsAnnotation]; v != "" {
+		if err := json.Unmarshal([]byte(v), &tolerations); err != nil {
+			return fmt.Errorf("namespace %s: invalid default tolerations: %w", namespace, err)
+		}
+	}
+
+	if len(selector) == 0 && len(tolerations) == 0 {
+		return nil
+	}
+
+	// -------------------------------------------------------------------------
+	// Node selector: merged, conflicts are rejected
+	// -------------------------------------------------------------------------
+	var errs field.ErrorList
+
+	if len(selector) > 0 && pod.Spec.NodeName != "" {
+		errs = append(errs, field.Forbidden(
+			field.NewPath("spec", "nodeName"),
+			"pods of this tenant are scheduled on its node pool and cannot set nodeName",
+		))
+	}
+
+	if len(selector) > 0 && pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = map[string]string{}
+	}
+	for k, v := range selector {
+		if current, ok := pod.Spec.NodeSelector[k]; ok && current != v {
+			errs = append(errs, field.Forbidden(
+				field.NewPath("spec", "nodeSelector").Key(k),
+				fmt.Sprintf("conflicts with the tenant node pool (%s=%s)", k, v),
+			))
+			continue
+		}
+		pod.Spec.NodeSelector[k] = v
+	}
+
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(
+			corev1.SchemeGroupVersion.WithKind("Pod").GroupKind(),
+			pod.Name,
+			errs,
+		)
+	}
+
+	// -------------------------------------------------------------------------
+	// Tolerations: added when missing
+	// -------------------------------------------------------------------------
+	for _, t := range tolerations {
+		if !hasToleration(pod.Spec.Tolerations, t) {
+			pod.Spec.Tolerations = append(pod.Spec.Tolerations, t)
+		}
+	}
+
+	return nil
+}
+
+// hasToleration reports whether tolerations already contains t.
+func hasToleration(tolerations []corev1.Toleration, t corev1.Toleration) bool {
+	for i := range tolerations {
+		if tolerations[i].MatchToleration(&t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPodPlacement_InjectsAndEnforcesPool(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "team-a",
+			Annotations: map[string]string{
+				platformv1alpha1.NodeSelectorAnnotation:       "pool=team-a",
+				platformv1alpha1.DefaultTolerationsAnnotation: `[{"key":"pool","operator":"Equal","value":"team-a","effect":"NoSchedule"}]`,
+			},
+		},
+	}
+
+	w := &PodPlacement{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns).Build(),
+	}
+
+	podWith := func(mutate func(*corev1.Pod)) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a"},
+		}
+		if mutate != nil {
+			mutate(pod)
+		}
+		return pod
+	}
+
+	// Selector and toleration are injected
+	pod := podWith(nil)
+	g.Expect(w.Default(context.Background(), pod)).To(Succeed())
+	g.Expect(pod.Spec.NodeSelector).To(HaveKeyWithValue("pool", "team-a"))
+	g.Expect(pod.Spec.Tolerations).To(HaveLen(1))
+
+	// Existing toleration is not duplicated, unrelated selectors are kept
+	pod = podWith(func(p *corev1.Pod) {
+		p.Spec.NodeSelector = map[string]string{"disk": "ssd"}
+		p.Spec.Tolerations = []corev1.Toleration{{
+			Key: "pool", Operator: corev1.TolerationOpEqual, Value: "team-a", Effect: corev1.TaintEffectNoSchedule,
+		}}
+	})
+	g.Expect(w.Default(context.Background(), pod)).To(Succeed())
+	g.Expect(pod.Spec.NodeSelector).To(HaveLen(2))
+	g.Expect(pod.Spec.Tolerations).To(HaveLen(1))
+
+	// Conflicting selector is rejected
+	pod = podWith(func(p *corev1.Pod) {
+		p.Spec.NodeSelector = map[string]string{"pool": "shared"}
+	})
+	g.Expect(w.Default(context.Background(), pod)).To(MatchError(ContainSubstring("conflicts with the tenant node pool")))
+
+	// Binding to a node directly is rejected
+	pod = podWith(func(p *corev1.Pod) {
+		p.Spec.NodeName = "node-1"
+	})
+	g.Expect(w.Default(context.Background(), pod)).To(MatchError(ContainSubstring("nodeName")))
+
+	// Namespaces without placement are left alone
+	other := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "unknown"}}
+	g.Expect(w.Default(context.Background(), other)).To(Succeed())
+	g.Expect(other.Spec.NodeSelector).To(BeEmpty())
+}
@@ -0,0 +1,292 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/config"
+
+	"github.com/robfig/cron/v3"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-platform-example-com-v1alpha1-tenant,mutating=false,failurePolicy=fail,sideEffects=None,groups=platform.example.com,resources=tenants,verbs=create;update,versions=v1alpha1,name=vtenant.platform.example.com,admissionReviewVersions=v1
+
+// -----------------------------------------------------------------------------
+// TenantValidator rejects Tenants that request a Pod Security enforce level
+// below the minimum permitted by their profile, carry an invalid
+// hibernation schedule, expiration, shard label or access role, or target a
+// reserved namespace.
+// -----------------------------------------------------------------------------
+type TenantValidator struct {
+	Client client.Reader
+
+	// Config is the operator configuration, the defaults when nil
+	Config *config.Store
+}
+
+var _ admission.CustomValidator = &TenantValidator{}
+
+func (v *TenantValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&platformv1alpha1.Tenant{}).
+		WithValidator(v).
+		Complete()
+}
+
+func (v *TenantValidator) ValidateCreate(
+	ctx context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	tenant, ok := obj.(*platformv1alpha1.Tenant)
+	if !ok {
+		return nil, fmt.Errorf("expected a Tenant but got %T", obj)
+	}
+	return v.validate(ctx, tenant, nil)
+}
+
+// -----------------------------------------------------------------------------
+// ValidateUpdate only checks the fields that changed: a configuration reload
+// or a profile change must not block updates of existing Tenants, the
+// finalizer removal of a deleted Tenant above all.
+// -----------------------------------------------------------------------------
+func (v *TenantValidator) ValidateUpdate(
+	ctx context.Context,
+	oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	tenant, ok := newObj.(*platformv1alpha1.Tenant)
+	if !ok {
+		return nil, fmt.Errorf("expected a Tenant but got %T", newObj)
+	}
+	old, ok := oldObj.(*platformv1alpha1.Tenant)
+	if !ok {
+		return nil, fmt.Errorf("expected a Tenant but got %T", oldObj)
+	}
+
+	if tenant.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	if equality.Semantic.DeepEqual(old.Spec, tenant.Spec) &&
+		maps.Equal(old.Labels, tenant.Labels) &&
+		maps.Equal(old.Annotations, tenant.Annotations) {
+		return nil, nil
+	}
+	return v.validate(ctx, tenant, old)
+}
+
+func (v *TenantValidator) ValidateDelete(
+	_ context.Context,
+	_ runtime.Object,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// -----------------------------------------------------------------------------
+// validate checks a created Tenant, or an updated one against its previous
+// version old: the checks depending on the profile or the configuration are
+// skipped for fields left unchanged.
+// -----------------------------------------------------------------------------
+func (v *TenantValidator) validate(
+	ctx context.Context,
+	tenant, old *platformv1alpha1.Tenant,
+) (admission.Warnings, error) {
+
+	var errs field.ErrorList
+	errs = append(errs, validateSchedule(tenant)...)
+	errs = append(errs, validateExpiry(tenant)...)
+
+	if old == nil ||
+		!equality.Semantic.DeepEqual(old.Spec.PodSecurity, tenant.Spec.PodSecurity) ||
+		!equality.Semantic.DeepEqual(old.Spec.Profile, tenant.Spec.Profile) {
+		errs = append(errs, v.validatePodSecurity(ctx, tenant)...)
+	}
+	if old == nil || old.Spec.Namespace != tenant.Spec.Namespace {
+		errs = append(errs, v.validateNamespace(tenant)...)
+	}
+	if old == nil || old.Labels[platformv1alpha1.ShardLabel] != tenant.Labels[platformv1alpha1.ShardLabel] {
+		errs = append(errs, v.validateShard(tenant)...)
+	}
+	if old == nil || !equality.Semantic.DeepEqual(old.Spec.Access, tenant.Spec.Access) {
+		errs = append(errs, v.validateAccess(tenant)...)
+	}
+
+	if len(errs) == 0 {
+		return nil, nil
+	}
+
+	return nil, apierrors.NewInvalid(
+		platformv1alpha1.GroupVersion.WithKind("Tenant").GroupKind(),
+		tenant.Name,
+		errs,
+	)
+}
+
+// validateNamespace rejects the namespaces reserved by the operator configuration
+func (v *TenantValidator) validateNamespace(tenant *platformv1alpha1.Tenant) field.ErrorList {
+	if !v.Config.Get().IsReserved(tenant.Spec.Namespace) {
+		return nil
+	}
+	return field.ErrorList{
+		field.Forbidden(field.NewPath("spec", "namespace"), "namespace is reserved"),
+	}
+}
+
+// validateAccess rejects access bindings to ClusterRoles the operator may not bind
+func (v *TenantValidator) validateAccess(tenant *platformv1alpha1.Tenant) field.ErrorList {
+	allowed := v.Config.Get().Access.AllowedRoles
+
+	var errs field.ErrorList
+	for i, b := range tenant.Spec.Access {
+		if !slices.Contains(allowed, b.Role) {
+			errs = append(errs, field.NotSupported(
+				field.NewPath("spec", "access").Index(i).Child("role"), b.Role, allowed,
+			))
+		}
+	}
+	return errs
+}
+
+// validateShard rejects a shard label naming no shard of the operator
+func (v *TenantValidator) validateShard(tenant *platformv1alpha1.Tenant) field.ErrorList {
+	value, ok := tenant.Labels[platformv1alpha1.ShardLabel]
+	if !ok {
+		return nil
+	}
+	if _, err := config.ParseShard(value, v.Config.Get().Sharding.Shards); err != nil {
+		path := field.NewPath("metadata", "labels").Key(platformv1alpha1.ShardLabel)
+		return field.ErrorList{field.Invalid(path, value, err.Error())}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// validatePodSecurity checks the requested enforce level against the profile,
+// or against the operator podSecurity.minimumEnforce without a profile.
+// -----------------------------------------------------------------------------
+func (v *TenantValidator) validatePodSecurity(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+) field.ErrorList {
+
+	if tenant.Spec.PodSecurity == nil || tenant.Spec.PodSecurity.Enforce == "" {
+		return nil
+	}
+
+	path := field.NewPath("spec", "podSecurity", "enforce")
+	requested := tenant.Spec.PodSecurity.Enforce
+
+	if tenant.Spec.Profile == nil {
+		minimum := v.Config.Get().PodSecurity.MinimumEnforce
+		if platformv1alpha1.PodSecurityLevelRank(requested) <
+			platformv1alpha1.PodSecurityLevelRank(minimum) {
+			return field.ErrorList{
+				field.Forbidden(
+					path,
+					fmt.Sprintf("level %q is below the operator minimum %q", requested, minimum),
+				),
+			}
+		}
+		return nil
+	}
+
+	profile := &platformv1alpha1.TenantProfile{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Name: *tenant.Spec.Profile}, profile); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The reconciler reports missing profiles; nothing to compare against
+			return nil
+		}
+		return field.ErrorList{field.InternalError(path, err)}
+	}
+
+	minimum := profile.Spec.PodSecurity.MinimumEnforceLevel()
+
+	if platformv1alpha1.PodSecurityLevelRank(requested) <
+		platformv1alpha1.PodSecurityLevelRank(minimum) {
+		return field.ErrorList{
+			field.Forbidden(
+				path,
+				fmt.Sprintf(
+					"level %q is below the minimum %q permitted by profile %q",
+					requested, minimum, profile.Name,
+				),
+			),
+		}
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// validateSchedule checks the cron expressions and time zone of the
+// hibernation schedule, and the wake-until annotation.
+// -----------------------------------------------------------------------------
+func validateSchedule(tenant *platformv1alpha1.Tenant) field.ErrorList {
+
+	var errs field.ErrorList
+
+	if v, ok := tenant.Annotations[platformv1alpha1.WakeUntilAnnotation]; ok {
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			errs = append(errs, field.Invalid(
+				field.NewPath("metadata", "annotations").Key(platformv1alpha1.WakeUntilAnnotation),
+				v,
+				"must be an RFC 3339 time",
+			))
+		}
+	}
+
+	schedule := tenant.Spec.Schedule
+	if schedule == nil {
+		return errs
+	}
+	path := field.NewPath("spec", "schedule")
+
+	if _, err := cron.ParseStandard(schedule.Sleep); err != nil {
+		errs = append(errs, field.Invalid(path.Child("sleep"), schedule.Sleep, err.Error()))
+	}
+	if _, err := cron.ParseStandard(schedule.Wake); err != nil {
+		errs = append(errs, field.Invalid(path.Child("wake"), schedule.Wake, err.Error()))
+	}
+	if schedule.TimeZone != "" {
+		if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+			errs = append(errs, field.Invalid(path.Child("timeZone"), schedule.TimeZone, "unknown time zone"))
+		}
+	}
+
+	return errs
+}
+
+// -----------------------------------------------------------------------------
+// validateExpiry checks the ttl and the extend-until annotation.
+// -----------------------------------------------------------------------------
+func validateExpiry(tenant *platformv1alpha1.Tenant) field.ErrorList {
+
+	var errs field.ErrorList
+
+	if v, ok := tenant.Annotations[platformv1alpha1.ExtendUntilAnnotation]; ok {
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			errs = append(errs, field.Invalid(
+				field.NewPath("metadata", "annotations").Key(platformv1alpha1.ExtendUntilAnnotation),
+				v,
+				"must be an RFC 3339 time",
+			))
+		}
+	}
+
+	if ttl := tenant.Spec.TTL; ttl != nil && ttl.Duration <= 0 {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "ttl"), ttl.Duration.String(), "must be positive"))
+	}
+
+	return errs
+}
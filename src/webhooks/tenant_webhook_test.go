@@ -0,0 +1,314 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+	"github.com/tngs/namespace-operator/config"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTenantValidator_PodSecurityMinimum(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(platformv1alpha1.AddToScheme(scheme))
+
+	profile := &platformv1alpha1.TenantProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "standard"},
+		Spec: platformv1alpha1.TenantProfileSpec{
+			PodSecurity: &platformv1alpha1.PodSecurityProfileSpec{
+				MinimumEnforce: platformv1alpha1.PodSecurityLevelBaseline,
+			},
+		},
+	}
+
+	validator := &TenantValidator{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(profile).
+			Build(),
+	}
+
+	tenantWith := func(level string) *platformv1alpha1.Tenant {
+		return &platformv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: platformv1alpha1.TenantSpec{
+				Namespace: "team-a",
+				Profile:   &profile.Name,
+				PodSecurity: &platformv1alpha1.PodSecuritySpec{
+					Enforce: level,
+				},
+			},
+		}
+	}
+
+	// At or above the minimum
+	_, err := validator.ValidateCreate(context.Background(), tenantWith(platformv1alpha1.PodSecurityLevelBaseline))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = validator.ValidateCreate(context.Background(), tenantWith(platformv1alpha1.PodSecurityLevelRestricted))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Below the minimum
+	_, err = validator.ValidateUpdate(
+		context.Background(),
+		tenantWith(platformv1alpha1.PodSecurityLevelBaseline),
+		tenantWith(platformv1alpha1.PodSecurityLevelPrivileged),
+	)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("below the minimum"))
+}
+
+func TestTenantValidator_PodSecurityOperatorMinimum(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(platformv1alpha1.AddToScheme(scheme))
+
+	validator := &TenantValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+
+	tenantWith := func(level string) *platformv1alpha1.Tenant {
+		return &platformv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: platformv1alpha1.TenantSpec{
+				Namespace:   "team-a",
+				PodSecurity: &platformv1alpha1.PodSecuritySpec{Enforce: level},
+			},
+		}
+	}
+
+	// Tenants without a profile are held to the operator minimum, baseline
+	_, err := validator.ValidateCreate(context.Background(), tenantWith(platformv1alpha1.PodSecurityLevelBaseline))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = validator.ValidateCreate(context.Background(), tenantWith(platformv1alpha1.PodSecurityLevelPrivileged))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("below the operator minimum"))
+}
+
+func TestTenantValidator_Schedule(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(platformv1alpha1.AddToScheme(scheme))
+
+	validator := &TenantValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev"},
+		Spec: platformv1alpha1.TenantSpec{
+			Namespace: "dev",
+			Schedule: &platformv1alpha1.HibernationSchedule{
+				Sleep:    "0 19 * * 1-5",
+				Wake:     "0 7 * * 1-5",
+				TimeZone: "Europe/Paris",
+			},
+		},
+	}
+
+	_, err := validator.ValidateCreate(context.Background(), tenant)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	invalid := tenant.DeepCopy()
+	invalid.Spec.Schedule.Wake = "every morning"
+	invalid.Spec.Schedule.TimeZone = "Mars/Olympus"
+	invalid.Annotations = map[string]string{platformv1alpha1.WakeUntilAnnotation: "tomorrow"}
+
+	_, err = validator.ValidateCreate(context.Background(), invalid)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("spec.schedule.wake"))
+	g.Expect(err.Error()).To(ContainSubstring("spec.schedule.timeZone"))
+	g.Expect(err.Error()).To(ContainSubstring("RFC 3339"))
+}
+
+func TestTenantValidator_Expiry(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(platformv1alpha1.AddToScheme(scheme))
+
+	validator := &TenantValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+
+	tenant := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "preview-42",
+			Annotations: map[string]string{platformv1alpha1.ExtendUntilAnnotation: "2026-10-20T18:00:00Z"},
+		},
+		Spec: platformv1alpha1.TenantSpec{
+			Namespace: "preview-42",
+			TTL:       &metav1.Duration{Duration: 72 * time.Hour},
+		},
+	}
+
+	_, err := validator.ValidateCreate(context.Background(), tenant)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	invalid := tenant.DeepCopy()
+	invalid.Spec.TTL.Duration = -time.Hour
+	invalid.Annotations[platformv1alpha1.ExtendUntilAnnotation] = "next week"
+
+	_, err = validator.ValidateCreate(context.Background(), invalid)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("spec.ttl"))
+	g.Expect(err.Error()).To(ContainSubstring("RFC 3339"))
+}
+
+func TestTenantValidator_ReservedNamespace(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(platformv1alpha1.AddToScheme(scheme))
+
+	cfg := config.Default()
+	cfg.ReservedNamespaces = append(cfg.ReservedNamespaces, "platform")
+
+	validator := &TenantValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Config: config.NewStore(cfg),
+	}
+
+	tenantFor := func(namespace string) *platformv1alpha1.Tenant {
+		return &platformv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+			Spec:       platformv1alpha1.TenantSpec{Namespace: namespace},
+		}
+	}
+
+	_, err := validator.ValidateCreate(context.Background(), tenantFor("team-a"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for _, ns := range []string{"kube-system", "platform"} {
+		_, err = validator.ValidateCreate(context.Background(), tenantFor(ns))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("spec.namespace"))
+	}
+}
+
+func TestTenantValidator_Shard(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(platformv1alpha1.AddToScheme(scheme))
+
+	cfg := config.Default()
+	cfg.Sharding = config.ShardingConfig{Shards: 3, Shard: 0}
+
+	validator := &TenantValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Config: config.NewStore(cfg),
+	}
+
+	tenantFor := func(shard string) *platformv1alpha1.Tenant {
+		return &platformv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "team-a",
+				Labels: map[string]string{platformv1alpha1.ShardLabel: shard},
+			},
+			Spec: platformv1alpha1.TenantSpec{Namespace: "team-a"},
+		}
+	}
+
+	_, err := validator.ValidateCreate(context.Background(), tenantFor("2"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for _, shard := range []string{"3", "-1", "two"} {
+		_, err = validator.ValidateCreate(context.Background(), tenantFor(shard))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring(platformv1alpha1.ShardLabel))
+	}
+}
+
+func TestTenantValidator_AccessRoles(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(platformv1alpha1.AddToScheme(scheme))
+
+	validator := &TenantValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+
+	tenantFor := func(role string) *platformv1alpha1.Tenant {
+		return &platformv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: platformv1alpha1.TenantSpec{
+				Namespace: "team-a",
+				Access:    []platformv1alpha1.AccessBinding{{Role: role, Groups: []string{"team-a"}}},
+			},
+		}
+	}
+
+	_, err := validator.ValidateCreate(context.Background(), tenantFor("edit"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = validator.ValidateCreate(context.Background(), tenantFor("cluster-admin"))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("spec.access[0].role"))
+}
+
+func TestTenantValidator_UpdateAfterReload(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(platformv1alpha1.AddToScheme(scheme))
+
+	// Valid when created, invalid once platform is reserved and edit no
+	// longer allowed
+	cfg := config.Default()
+	cfg.ReservedNamespaces = append(cfg.ReservedNamespaces, "platform")
+	cfg.Access.AllowedRoles = []string{"admin", "view"}
+
+	validator := &TenantValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Config: config.NewStore(cfg),
+	}
+
+	old := &platformv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+		Spec: platformv1alpha1.TenantSpec{
+			Namespace: "platform",
+			Access:    []platformv1alpha1.AccessBinding{{Role: "edit", Groups: []string{"team-a"}}},
+		},
+	}
+
+	// Finalizer changes, on a live or a deleted Tenant
+	updated := old.DeepCopy()
+	updated.Finalizers = []string{"platform.example.com/finalizer"}
+	_, err := validator.ValidateUpdate(ctx, old, updated)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	now := metav1.Now()
+	deleted := old.DeepCopy()
+	deleted.DeletionTimestamp = &now
+	_, err = validator.ValidateUpdate(ctx, updated, deleted)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Only the changed fields are checked
+	updated = old.DeepCopy()
+	updated.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+	_, err = validator.ValidateUpdate(ctx, old, updated)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	updated.Spec.Access = append(updated.Spec.Access, platformv1alpha1.AccessBinding{Role: "edit", Users: []string{"bob"}})
+	_, err = validator.ValidateUpdate(ctx, old, updated)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("spec.access"))
+	g.Expect(err.Error()).NotTo(ContainSubstring("spec.namespace"))
+}
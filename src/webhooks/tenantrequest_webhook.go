@@ -0,0 +1,237 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ApproveVerb is the verb on tenantrequests a user needs to approve requests
+const ApproveVerb = "approve"
+
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// +kubebuilder:webhook:path=/mutate-platform-example-com-v1alpha1-tenantrequest,mutating=true,failurePolicy=fail,sideEffects=None,groups=platform.example.com,resources=tenantrequests,verbs=create;update,versions=v1alpha1,name=mtenantrequest.platform.example.com,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-platform-example-com-v1alpha1-tenantrequest,mutating=false,failurePolicy=fail,sideEffects=None,groups=platform.example.com,resources=tenantrequests,verbs=create;update,versions=v1alpha1,name=vtenantrequest.platform.example.com,admissionReviewVersions=v1
+
+// -----------------------------------------------------------------------------
+// TenantRequestWebhook records the authenticated requester and approver on
+// TenantRequests, and rejects requests for a group the requester is not a
+// member of, approvals by users without the approve verb on the request,
+// self-approvals and spec changes after creation.
+// -----------------------------------------------------------------------------
+type TenantRequestWebhook struct {
+	// Client creates the SubjectAccessReviews checking approvers
+	Client client.Client
+}
+
+var (
+	_ admission.CustomDefaulter = &TenantRequestWebhook{}
+	_ admission.CustomValidator = &TenantRequestWebhook{}
+)
+
+func (w *TenantRequestWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&platformv1alpha1.TenantRequest{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+// -----------------------------------------------------------------------------
+// Default sets the requester on create and keeps it on update. The approver
+// is recorded when the approved annotation is first set to "true"; neither
+// annotation can be set by the user directly.
+// -----------------------------------------------------------------------------
+func (w *TenantRequestWebhook) Default(
+	ctx context.Context,
+	obj runtime.Object,
+) error {
+
+	treq, ok := obj.(*platformv1alpha1.TenantRequest)
+	if !ok {
+		return fmt.Errorf("expected a TenantRequest but got %T", obj)
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	old := &platformv1alpha1.TenantRequest{}
+	if req.Operation == admissionv1.Update {
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return err
+		}
+	}
+
+	if treq.Annotations == nil {
+		treq.Annotations = map[string]string{}
+	}
+
+	// Requester
+	requester := req.UserInfo.Username
+	if req.Operation == admissionv1.Update {
+		requester = old.Annotations[platformv1alpha1.TenantRequestRequesterAnnotation]
+	}
+	treq.Annotations[platformv1alpha1.TenantRequestRequesterAnnotation] = requester
+
+	// Approver
+	delete(treq.Annotations, platformv1alpha1.TenantRequestApprovedByAnnotation)
+
+	switch {
+	case !approved(treq):
+	case approved(old):
+		if approver := old.Annotations[platformv1alpha1.TenantRequestApprovedByAnnotation]; approver != "" {
+			treq.Annotations[platformv1alpha1.TenantRequestApprovedByAnnotation] = approver
+		}
+	default:
+		treq.Annotations[platformv1alpha1.TenantRequestApprovedByAnnotation] = req.UserInfo.Username
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (w *TenantRequestWebhook) ValidateCreate(
+	ctx context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	return w.validate(ctx, nil, obj)
+}
+
+func (w *TenantRequestWebhook) ValidateUpdate(
+	ctx context.Context,
+	oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	return w.validate(ctx, oldObj, newObj)
+}
+
+func (w *TenantRequestWebhook) ValidateDelete(
+	_ context.Context,
+	_ runtime.Object,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (w *TenantRequestWebhook) validate(
+	ctx context.Context,
+	oldObj, obj runtime.Object,
+) (admission.Warnings, error) {
+
+	treq, ok := obj.(*platformv1alpha1.TenantRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected a TenantRequest but got %T", obj)
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs field.ErrorList
+
+	if oldObj == nil {
+		// Only members may request a tenant for a group
+		if !slices.Contains(req.UserInfo.Groups, treq.Spec.Group) {
+			errs = append(errs, field.Forbidden(
+				field.NewPath("spec", "group"),
+				fmt.Sprintf("user %q is not a member of group %q", req.UserInfo.Username, treq.Spec.Group),
+			))
+		}
+	} else if old, ok := oldObj.(*platformv1alpha1.TenantRequest); ok &&
+		!equality.Semantic.DeepEqual(old.Spec, treq.Spec) {
+		errs = append(errs, field.Forbidden(
+			field.NewPath("spec"),
+			"spec is immutable, create a new TenantRequest instead",
+		))
+	}
+
+	approvedPath := field.NewPath("metadata", "annotations").Key(platformv1alpha1.TenantRequestApprovedAnnotation)
+	approver := treq.Annotations[platformv1alpha1.TenantRequestApprovedByAnnotation]
+	if approved(treq) &&
+		approver == treq.Annotations[platformv1alpha1.TenantRequestRequesterAnnotation] {
+		errs = append(errs, field.Forbidden(approvedPath, "requests cannot be approved by their requester"))
+	}
+
+	// The user setting the approval must be allowed to approve the request
+	if old, ok := oldObj.(*platformv1alpha1.TenantRequest); ok && approved(treq) && !approved(old) {
+		allowed, err := w.mayApprove(ctx, req.UserInfo, treq)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			errs = append(errs, field.Forbidden(approvedPath, fmt.Sprintf(
+				"user %q may not approve TenantRequests, the %q verb on tenantrequests is required",
+				req.UserInfo.Username, ApproveVerb,
+			)))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil, nil
+	}
+
+	return nil, apierrors.NewInvalid(
+		platformv1alpha1.GroupVersion.WithKind("TenantRequest").GroupKind(),
+		treq.Name,
+		errs,
+	)
+}
+
+// -----------------------------------------------------------------------------
+// mayApprove asks the API server, with a SubjectAccessReview, whether user
+// holds the approve verb on the request.
+// -----------------------------------------------------------------------------
+func (w *TenantRequestWebhook) mayApprove(
+	ctx context.Context,
+	user authenticationv1.UserInfo,
+	treq *platformv1alpha1.TenantRequest,
+) (bool, error) {
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: treq.Namespace,
+				Verb:      ApproveVerb,
+				Group:     platformv1alpha1.GroupVersion.Group,
+				Resource:  "tenantrequests",
+				Name:      treq.Name,
+			},
+		},
+	}
+	if err := w.Client.Create(ctx, review); err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// approved reports whether the request carries the approval annotation.
+func approved(treq *platformv1alpha1.TenantRequest) bool {
+	return treq.Annotations[platformv1alpha1.TenantRequestApprovedAnnotation] == "true"
+}
@@ -0,0 +1,142 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	platformv1alpha1 "github.com/tngs/namespace-operator/api/v1alpha1"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestTenantRequestWebhook_RequesterAndApprover(t *testing.T) {
+	g := NewWithT(t)
+
+	// Only bob and carol hold the approve verb, carol outside the requests
+	// namespace
+	var reviews []authorizationv1.ResourceAttributes
+	w := &TenantRequestWebhook{
+		Client: fake.NewClientBuilder().
+			WithScheme(clientgoscheme.Scheme).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					review := obj.(*authorizationv1.SubjectAccessReview)
+					attrs := review.Spec.ResourceAttributes
+					reviews = append(reviews, *attrs)
+					review.Status.Allowed = review.Spec.User == "bob" ||
+						review.Spec.User == "carol" && attrs.Namespace != "requests"
+					return nil
+				},
+			}).
+			Build(),
+	}
+
+	contextFor := func(op admissionv1.Operation, user string, old *platformv1alpha1.TenantRequest) context.Context {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: op,
+			UserInfo: authenticationv1.UserInfo{
+				Username: user,
+				Groups:   []string{"team-a"},
+			},
+		}}
+		if old != nil {
+			raw, err := json.Marshal(old)
+			g.Expect(err).NotTo(HaveOccurred())
+			req.OldObject = runtime.RawExtension{Raw: raw}
+		}
+		return admission.NewContextWithRequest(context.Background(), req)
+	}
+
+	// Create: requester comes from the user, a forged approver is dropped
+	treq := &platformv1alpha1.TenantRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "team-a",
+			Namespace: "requests",
+			Annotations: map[string]string{
+				platformv1alpha1.TenantRequestRequesterAnnotation:  "someone-else",
+				platformv1alpha1.TenantRequestApprovedByAnnotation: "someone-else",
+			},
+		},
+		Spec: platformv1alpha1.TenantRequestSpec{
+			Namespace: "team-a",
+			Profile:   "standard",
+			Group:     "team-a",
+		},
+	}
+
+	ctx := contextFor(admissionv1.Create, "alice", nil)
+	g.Expect(w.Default(ctx, treq)).To(Succeed())
+	g.Expect(treq.Annotations).To(HaveKeyWithValue(platformv1alpha1.TenantRequestRequesterAnnotation, "alice"))
+	g.Expect(treq.Annotations).NotTo(HaveKey(platformv1alpha1.TenantRequestApprovedByAnnotation))
+
+	_, err := w.ValidateCreate(ctx, treq)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Create for a group the user is not a member of
+	other := treq.DeepCopy()
+	other.Spec.Group = "team-b"
+	_, err = w.ValidateCreate(ctx, other)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("not a member"))
+
+	// Self-approval is rejected
+	old := treq.DeepCopy()
+	self := treq.DeepCopy()
+	self.Annotations[platformv1alpha1.TenantRequestApprovedAnnotation] = "true"
+
+	ctx = contextFor(admissionv1.Update, "alice", old)
+	g.Expect(w.Default(ctx, self)).To(Succeed())
+	_, err = w.ValidateUpdate(ctx, old, self)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("approved by their requester"))
+
+	// Approval by someone else records the approver and keeps the requester
+	approvedReq := treq.DeepCopy()
+	approvedReq.Annotations[platformv1alpha1.TenantRequestApprovedAnnotation] = "true"
+	approvedReq.Annotations[platformv1alpha1.TenantRequestRequesterAnnotation] = "bob"
+
+	ctx = contextFor(admissionv1.Update, "bob", old)
+	g.Expect(w.Default(ctx, approvedReq)).To(Succeed())
+	g.Expect(approvedReq.Annotations).To(HaveKeyWithValue(platformv1alpha1.TenantRequestRequesterAnnotation, "alice"))
+	g.Expect(approvedReq.Annotations).To(HaveKeyWithValue(platformv1alpha1.TenantRequestApprovedByAnnotation, "bob"))
+
+	_, err = w.ValidateUpdate(ctx, old, approvedReq)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reviews).To(ContainElement(authorizationv1.ResourceAttributes{
+		Namespace: "requests",
+		Verb:      ApproveVerb,
+		Group:     platformv1alpha1.GroupVersion.Group,
+		Resource:  "tenantrequests",
+		Name:      "team-a",
+	}))
+
+	// Approval by a user without the approve verb on the request
+	approvedReq = treq.DeepCopy()
+	approvedReq.Annotations[platformv1alpha1.TenantRequestApprovedAnnotation] = "true"
+
+	ctx = contextFor(admissionv1.Update, "carol", old)
+	g.Expect(w.Default(ctx, approvedReq)).To(Succeed())
+	_, err = w.ValidateUpdate(ctx, old, approvedReq)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("may not approve"))
+
+	// Spec is immutable
+	changed := approvedReq.DeepCopy()
+	changed.Spec.Profile = "premium"
+	_, err = w.ValidateUpdate(ctx, old, changed)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("immutable"))
+}